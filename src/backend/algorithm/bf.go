@@ -1,53 +1,84 @@
 package algorithm
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
-// Brute Force algorithm to solve dot-connect
-func BruteForce(board [][]int) ([][2]int, bool) {
-	fmt.Println("[BruteForce] starting algorithm")
+// Solver using the Brute Force algorithm
+type bruteForceSolver struct{}
 
-	startPoint, usableDotCount, solvable := PreCheckBoard(board)
-	if !solvable {
-		return nil, false
+func init() {
+	Register(bruteForceSolver{})
+}
+
+func (bruteForceSolver) Name() string {
+	return "bf"
+}
+
+func (bruteForceSolver) Solve(ctx context.Context, board [][]int, opts Options) Result {
+	return BruteForce(ctx, board, opts)
+}
+
+// Brute Force algorithm to solve dot-connect.
+// Checks every path without any pruning, it is the baseline the other solvers are compared to.
+func BruteForce(ctx context.Context, board [][]int, opts Options) Result {
+	fmt.Fprintln(logOutput, "[BruteForce] starting algorithm")
+
+	startPoint, usableDotCount, err := PreCheckBoard(board)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
 	}
 
-	visited := make(map[[2]int]bool)
+	prefix, err := startingPath(board, startPoint, opts.Prefix)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
 
-	path, found := BruteForceRecursive(board, startPoint[0], startPoint[1], visited, nil, usableDotCount)
+	// Continue the search from the last dot of the prefix
+	b := NewBitBoard(board)
+	for _, cell := range prefix[:len(prefix)-1] {
+		b.Visit(b.Index(cell[0], cell[1]))
+	}
+	head := prefix[len(prefix)-1]
+	s := newSearch(ctx, opts)
 
-	return path, found
+	// The path has room for every dot, so the recursion never reallocates it
+	currPath := make([][2]int, len(prefix)-1, usableDotCount)
+	copy(currPath, prefix)
+
+	path, found := BruteForceRecursive(s, b, b.Index(head[0], head[1]), currPath, usableDotCount)
+
+	return s.result(path, found)
 }
 
-func BruteForceRecursive(board [][]int, r, c int, visited map[[2]int]bool, currPath [][2]int, usableDotCount int) ([][2]int, bool) {
-	visited[[2]int{r, c}] = true
-	currPath = append(currPath, [2]int{r, c})
+func BruteForceRecursive(s *search, b *BitBoard, cell int, currPath [][2]int, usableDotCount int) ([][2]int, bool) {
+	if !s.expand(len(currPath) + 1) {
+		return nil, false
+	}
+
+	b.Visit(cell)
+	currPath = append(currPath, b.Cell(cell))
+	s.tracePush(b.Cell(cell), len(currPath))
 
 	if len(currPath) == usableDotCount {
 		return currPath, true
 	}
 
-	// Possible directions (up, down, left, right)
-	directions := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
-
-	// Explore neighbors
-	for _, dir := range directions {
-		newR := r + dir[0]
-		newC := c + dir[1]
-
-		if isValidMove(board, newR, newC, visited) {
-			path, found := BruteForceRecursive(board, newR, newC, visited, currPath, usableDotCount)
+	// Explore neighbors (up, down, left, right)
+	for _, neighbor := range b.Neighbors(cell) {
+		if neighbor != -1 && b.IsFree(neighbor) {
+			path, found := BruteForceRecursive(s, b, neighbor, currPath, usableDotCount)
 			if found {
 				return path, true
 			}
 		}
 	}
 
-	visited[[2]int{r, c}] = false
+	// Backtrack
+	s.stats.Backtracks++
+	b.Unvisit(cell)
+	s.traceBacktrack(b.Cell(cell), len(currPath)-1)
 
 	return nil, false
 }
-
-// Check if a move is valid
-func isValidMove(board [][]int, r, c int, visited map[[2]int]bool) bool {
-	return r >= 0 && r < len(board) && c >= 0 && c < len(board[0]) && board[r][c] != 1 && !visited[[2]int{r, c}]
-}
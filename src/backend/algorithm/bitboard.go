@@ -0,0 +1,138 @@
+package algorithm
+
+import "math/bits"
+
+// Offsets of the neighbours of a cell, in the order the grid solvers try them (up, down, left, right)
+var directions = [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
+// BitBoard is a board packed into a bitset, shared by the grid solvers.
+// Cell (r, c) is bit r*Cols+c; a bit is set while the cell is free, open and not yet visited.
+// The neighbours of every cell are precomputed, both as a list and as masks over the
+// words of the bitset, so counting free neighbours is a few popcounts.
+type BitBoard struct {
+	Rows int
+	Cols int
+
+	free      []uint64
+	neighbors [][4]int    // Open neighbours of every cell in directions order, -1 where there is none
+	masks     []cellMasks // Open neighbours of every cell as masks over free
+	openCount int         // Amount of open cells
+}
+
+// Neighbour mask of a cell, split over the words of the bitset it touches
+type cellMasks struct {
+	count int
+	words [4]int
+	masks [4]uint64
+}
+
+// Pack a board, 1 is a blocked cell and anything else is open
+func NewBitBoard(board [][]int) *BitBoard {
+	rows, cols := len(board), len(board[0])
+	n := rows * cols
+	b := &BitBoard{
+		Rows:      rows,
+		Cols:      cols,
+		free:      make([]uint64, (n+63)/64),
+		neighbors: make([][4]int, n),
+		masks:     make([]cellMasks, n),
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if board[r][c] != 1 {
+				b.free[(r*cols+c)/64] |= 1 << ((r*cols + c) % 64)
+				b.openCount++
+			}
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		r, c := i/cols, i%cols
+		m := &b.masks[i]
+		for d, dir := range directions {
+			b.neighbors[i][d] = -1
+			newR, newC := r+dir[0], c+dir[1]
+			if newR < 0 || newR >= rows || newC < 0 || newC >= cols || board[newR][newC] == 1 {
+				continue
+			}
+
+			j := newR*cols + newC
+			b.neighbors[i][d] = j
+
+			// Merge the neighbours that fall in the same word
+			k := 0
+			for k < m.count && m.words[k] != j/64 {
+				k++
+			}
+			if k == m.count {
+				m.words[k] = j / 64
+				m.count++
+			}
+			m.masks[k] |= 1 << (j % 64)
+		}
+	}
+
+	return b
+}
+
+// Index of the cell (r, c)
+func (b *BitBoard) Index(r, c int) int {
+	return r*b.Cols + c
+}
+
+// Position of the cell at index i
+func (b *BitBoard) Cell(i int) [2]int {
+	return [2]int{i / b.Cols, i % b.Cols}
+}
+
+// Amount of open cells, visited or not
+func (b *BitBoard) OpenCount() int {
+	return b.openCount
+}
+
+// Check if the cell at index i is open and not visited
+func (b *BitBoard) IsFree(i int) bool {
+	return b.free[i/64]&(1<<(i%64)) != 0
+}
+
+// Check if (r, c) is on the board, open and not visited
+func (b *BitBoard) IsFreeAt(r, c int) bool {
+	return r >= 0 && r < b.Rows && c >= 0 && c < b.Cols && b.IsFree(r*b.Cols+c)
+}
+
+// Mark the cell at index i visited
+func (b *BitBoard) Visit(i int) {
+	b.free[i/64] &^= 1 << (i % 64)
+}
+
+// Mark the cell at index i not visited, it must be open
+func (b *BitBoard) Unvisit(i int) {
+	b.free[i/64] |= 1 << (i % 64)
+}
+
+// Open neighbours of the cell at index i in directions order, -1 where there is none
+func (b *BitBoard) Neighbors(i int) [4]int {
+	return b.neighbors[i]
+}
+
+// Check if the cells at indices i and j are open neighbours
+func (b *BitBoard) Adjacent(i, j int) bool {
+	m := &b.masks[i]
+	for k := 0; k < m.count; k++ {
+		if m.words[k] == j/64 {
+			return m.masks[k]&(1<<(j%64)) != 0
+		}
+	}
+	return false
+}
+
+// Amount of free neighbours of the cell at index i
+func (b *BitBoard) Connections(i int) int {
+	m := &b.masks[i]
+	connections := 0
+	for k := 0; k < m.count; k++ {
+		connections += bits.OnesCount64(b.free[m.words[k]] & m.masks[k])
+	}
+	return connections
+}
@@ -0,0 +1,78 @@
+package algorithm
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestBitBoardMatchesGrid(t *testing.T) {
+	// Wide and tall boards put the neighbours of a cell in different words
+	rng := rand.New(rand.NewSource(3))
+	for _, size := range [][2]int{{5, 5}, {3, 70}, {70, 3}, {9, 130}} {
+		board := make([][]int, size[0])
+		for r := range board {
+			board[r] = make([]int, size[1])
+			for c := range board[r] {
+				if rng.Intn(4) == 0 {
+					board[r][c] = 1
+				}
+			}
+		}
+
+		b := NewBitBoard(board)
+		visited := make(map[[2]int]bool)
+		for step := 0; step < 3; step++ {
+			for r := range board {
+				for c := range board[r] {
+					i := b.Index(r, c)
+					want := 0
+					for _, dir := range directions {
+						newR, newC := r+dir[0], c+dir[1]
+						free := newR >= 0 && newR < len(board) && newC >= 0 && newC < len(board[0]) &&
+							board[newR][newC] != 1 && !visited[[2]int{newR, newC}]
+						if free {
+							want++
+						}
+						if b.IsFreeAt(newR, newC) != free {
+							t.Fatalf("%v: IsFreeAt(%d, %d) = %v", size, newR, newC, !free)
+						}
+						if free && !b.Adjacent(i, b.Index(newR, newC)) {
+							t.Fatalf("%v: (%d, %d) and (%d, %d) not adjacent", size, r, c, newR, newC)
+						}
+					}
+					if got := b.Connections(i); got != want {
+						t.Fatalf("%v: Connections(%d, %d) = %d, want %d", size, r, c, got, want)
+					}
+				}
+			}
+
+			// Visit a few random open cells, and free one again
+			for k := 0; k < len(board)*len(board[0])/5; k++ {
+				r, c := rng.Intn(len(board)), rng.Intn(len(board[0]))
+				if board[r][c] != 1 {
+					b.Visit(b.Index(r, c))
+					visited[[2]int{r, c}] = true
+				}
+			}
+			for cell := range visited {
+				b.Unvisit(b.Index(cell[0], cell[1]))
+				delete(visited, cell)
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkGridSolvers(b *testing.B) {
+	for _, nb := range graphBenchBoards(b) {
+		for _, solver := range []Solver{bruteForceSolver{}, greedySolver{}} {
+			b.Run(nb.name+"/"+solver.Name(), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					solver.Solve(context.Background(), nb.board, Options{MaxNodes: 20000})
+				}
+			})
+		}
+	}
+}
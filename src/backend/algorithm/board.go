@@ -0,0 +1,92 @@
+package algorithm
+
+import "fmt"
+
+// PreCheckBoard checks if the board is solvable or not.
+// Returns the starting point and the amount of usable dots, or the reason the board is unsolvable.
+func PreCheckBoard(board [][]int) ([2]int, int, error) {
+	startPoint, err := checkBoard(board)
+	if err != nil {
+		return [2]int{0, 0}, 0, err
+	}
+
+	rows := len(board)
+	cols := len(board[0])
+
+	usableDotCount := 0
+	countEndPoint := 0
+	var colorCount [2]int
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if board[r][c] == 2 {
+				usableDotCount++
+				colorCount[cellColor(r, c)]++
+			}
+			if board[r][c] == 0 {
+				usableDotCount++
+				colorCount[cellColor(r, c)]++
+				connection := 0
+				if r+1 < rows && (board[r+1][c] == 0 || board[r+1][c] == 2) {
+					connection++
+				}
+				if r-1 >= 0 && (board[r-1][c] == 0 || board[r-1][c] == 2) {
+					connection++
+				}
+				if c+1 < cols && (board[r][c+1] == 0 || board[r][c+1] == 2) {
+					connection++
+				}
+				if c-1 >= 0 && (board[r][c-1] == 0 || board[r][c-1] == 2) {
+					connection++
+				}
+				if connection == 0 {
+					return [2]int{0, 0}, 0, fmt.Errorf("isolated dot detected at (%d, %d)", r, c)
+				} else if connection == 1 {
+					countEndPoint++
+					if countEndPoint > 1 {
+						return [2]int{0, 0}, 0, fmt.Errorf("amount of endpoint > 1")
+					}
+				}
+			}
+		}
+	}
+
+	if err := checkParity(colorCount, cellColor(startPoint[0], startPoint[1])); err != nil {
+		return [2]int{0, 0}, 0, err
+	}
+
+	return startPoint, usableDotCount, nil
+}
+
+// Check that the board has cells, that every row has the same length, that every cell is
+// a dot (0), a blocked cell (1) or the starting dot (2) and that there is exactly one starting dot.
+// Returns the position of the starting dot.
+func checkBoard(board [][]int) ([2]int, error) {
+	if len(board) == 0 || len(board[0]) == 0 {
+		return [2]int{}, fmt.Errorf("board is empty")
+	}
+	if !isRectangular(board) {
+		return [2]int{}, fmt.Errorf("board is not rectangular")
+	}
+
+	start, starts := [2]int{}, 0
+	for r, row := range board {
+		for c, cell := range row {
+			if cell < 0 || cell > 2 {
+				return [2]int{}, fmt.Errorf("invalid cell %d at (%d, %d)", cell, r, c)
+			}
+			if cell == 2 {
+				start = [2]int{r, c}
+				starts++
+			}
+		}
+	}
+
+	switch {
+	case starts == 0:
+		return [2]int{}, fmt.Errorf("no starting dot")
+	case starts > 1:
+		return [2]int{}, fmt.Errorf("more than one starting dot")
+	}
+	return start, nil
+}
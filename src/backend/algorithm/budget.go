@@ -0,0 +1,214 @@
+package algorithm
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Amount of expanded nodes between two checks of the context and the clock
+const checkInterval = 1024
+
+// Options limit the work a solver may do. Zero values mean no limit.
+type Options struct {
+	MaxTime  time.Duration // Maximum wall time of the search
+	MaxNodes int64         // Maximum amount of expanded nodes
+
+	DisablePruning bool // Search without the dead end pruning, mainly for benchmarks
+
+	// TranspositionSize is the amount of dead states DFS and Greedy remember, 8 bytes each.
+	// Zero disables the memo.
+	TranspositionSize int
+
+	// Prefix is the beginning of the path the search has to continue, it must start at the starting dot.
+	// Empty means the search starts from the starting dot alone.
+	Prefix [][2]int
+
+	// Trace receives every push and backtrack of the search as it happens, the search waits for it to return.
+	// The time spent waiting is left out of MaxTime.
+	// The parallel solver calls it from several goroutines, one at a time, and the events of each
+	// Event.Worker form a path of their own. The DP sends layers instead, see DP.
+	Trace func(Event)
+}
+
+// Status of a solver run
+type Status string
+
+const (
+	StatusFound       Status = "found"
+	StatusNotFound    Status = "not_found"
+	StatusTimedOut    Status = "timed_out"   // Budget ran out or the context was cancelled
+	StatusUnsupported Status = "unsupported" // The board is outside of what the solver can handle
+)
+
+// Result of a solver run
+type Result struct {
+	Path   [][2]int
+	Status Status
+	Reason string // Why the board was rejected without searching, if it was
+	Stats  Stats
+}
+
+// Check if the result holds a solution
+func (r Result) Found() bool {
+	return r.Status == StatusFound
+}
+
+// budget is shared by every goroutine of one solver run
+type budget struct {
+	ctx      context.Context
+	start    time.Time
+	deadline time.Time
+	maxNodes int64
+	prune    bool
+	memoSize int
+	trace    func(Event)
+	traced   atomic.Int64 // Nanoseconds spent in trace, they push the deadline back
+	nodes    atomic.Int64
+	exceeded atomic.Bool
+}
+
+// search is the state of one goroutine of a solver run.
+// Expanded nodes are counted locally and added to the shared budget every few nodes.
+type search struct {
+	*budget
+	pending   int64
+	nextCheck int64
+	pruner    *pruner // nil when pruning is disabled or not used by the solver
+	stats     Stats
+	worker    int // Reported in the trace events
+
+	memo        *transpositionTable // nil when the memo is disabled or not used by the solver
+	visitedHash uint64              // Zobrist hash of the visited dots
+}
+
+// Start a search limited by ctx and opts
+func newSearch(ctx context.Context, opts Options) *search {
+	b := &budget{
+		ctx:      ctx,
+		start:    time.Now(),
+		maxNodes: opts.MaxNodes,
+		prune:    !opts.DisablePruning,
+		memoSize: opts.TranspositionSize,
+	}
+	if opts.MaxTime > 0 {
+		b.deadline = b.start.Add(opts.MaxTime)
+	}
+	if opts.Trace != nil {
+		b.trace = func(e Event) {
+			start := time.Now()
+			opts.Trace(e)
+			b.traced.Add(int64(time.Since(start)))
+		}
+	}
+
+	return b.fork()
+}
+
+// Create another search sharing the budget, for use in a new goroutine
+func (b *budget) fork() *search {
+	s := &search{budget: b}
+	s.nextCheck = s.checkDistance(0)
+	return s
+}
+
+// Enable the dead end pruning over size dots, unless the options disabled it
+func (s *search) usePruner(size int) {
+	if s.prune {
+		s.pruner = newPruner(size)
+	}
+}
+
+// Enable the memo of dead states, unless the options disabled it
+func (s *search) useTranspositions() {
+	if s.memoSize > 0 {
+		s.memo = newTranspositionTable(s.memoSize)
+	}
+}
+
+// Mark the dot i visited or unvisited in the hash of the visited dots
+func (s *search) toggleVisited(i int) {
+	s.visitedHash ^= visitedKey(i)
+}
+
+// Check if the current visited dots with head i are a known dead state
+func (s *search) knownDead(head int) bool {
+	if s.memo == nil {
+		return false
+	}
+
+	s.stats.TranspositionLookups++
+	if s.memo.contains(s.visitedHash ^ headKey(head)) {
+		s.stats.TranspositionHits++
+		return true
+	}
+	return false
+}
+
+// Remember the current visited dots with head i as a dead state.
+// Nothing is remembered once the budget ran out, the state may not have been fully explored.
+func (s *search) markDead(head int) {
+	if s.memo != nil && !s.exceeded.Load() {
+		s.memo.add(s.visitedHash ^ headKey(head))
+	}
+}
+
+// Count one expanded node at depth (length of the path with the node), returns false once the search must stop
+func (s *search) expand(depth int) bool {
+	if s.exceeded.Load() {
+		return false
+	}
+
+	s.pending++
+	if s.pending >= s.nextCheck && !s.check() {
+		return false
+	}
+
+	s.stats.NodesExpanded++
+	if depth > s.stats.MaxDepth {
+		s.stats.MaxDepth = depth
+	}
+
+	return true
+}
+
+// Add the pending nodes to the budget and check every limit
+func (s *search) check() bool {
+	total := s.nodes.Add(s.pending)
+	s.pending = 0
+
+	if (s.maxNodes > 0 && total > s.maxNodes) ||
+		s.ctx.Err() != nil ||
+		(!s.deadline.IsZero() && time.Now().After(s.deadline.Add(time.Duration(s.traced.Load())))) {
+		s.exceeded.Store(true)
+		return false
+	}
+
+	s.nextCheck = s.checkDistance(total)
+	return true
+}
+
+// Amount of nodes until the next check, never past the node limit
+func (s *search) checkDistance(total int64) int64 {
+	if s.maxNodes > 0 && s.maxNodes-total+1 < checkInterval {
+		return s.maxNodes - total + 1
+	}
+	return checkInterval
+}
+
+// Build the result of a finished search
+func (s *search) result(path [][2]int, found bool) Result {
+	s.stats.TimeNs = time.Since(s.start).Nanoseconds()
+	if s.stats.TranspositionLookups > 0 {
+		s.stats.TranspositionHitRate = float64(s.stats.TranspositionHits) / float64(s.stats.TranspositionLookups)
+	}
+
+	switch {
+	case found:
+		return Result{Path: path, Status: StatusFound, Stats: s.stats}
+	case s.exceeded.Load():
+		return Result{Status: StatusTimedOut, Stats: s.stats}
+	default:
+		return Result{Status: StatusNotFound, Stats: s.stats}
+	}
+}
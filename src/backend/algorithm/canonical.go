@@ -0,0 +1,266 @@
+package algorithm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transform is one of the 8 symmetries of a board: Transform%4 clockwise quarter turns,
+// done after mirroring the columns when Transform >= 4
+type Transform int
+
+// Apply the transform to a board
+func (t Transform) Board(board [][]int) [][]int {
+	rows, cols := len(board), len(board[0])
+	newRows, newCols := rows, cols
+	if t%2 == 1 {
+		newRows, newCols = cols, rows
+	}
+
+	transformed := make([][]int, newRows)
+	for i := range transformed {
+		transformed[i] = make([]int, newCols)
+	}
+	for r := range board {
+		for c := range board[r] {
+			cell := t.Cell([2]int{r, c}, rows, cols)
+			transformed[cell[0]][cell[1]] = board[r][c]
+		}
+	}
+
+	return transformed
+}
+
+// Position of a cell of a rows x cols board once the transform is applied
+func (t Transform) Cell(cell [2]int, rows, cols int) [2]int {
+	r, c := cell[0], cell[1]
+	if t >= 4 {
+		c = cols - 1 - c
+	}
+	for i := 0; i < int(t%4); i++ {
+		r, c = c, rows-1-r
+		rows, cols = cols, rows
+	}
+	return [2]int{r, c}
+}
+
+// Position in a rows x cols board of a cell of the transformed board, undoing Cell
+func (t Transform) InverseCell(cell [2]int, rows, cols int) [2]int {
+	r, c := cell[0], cell[1]
+
+	// Columns of the board after each quarter turn is undone, starting from the transformed one
+	newCols := cols
+	if t%2 == 1 {
+		newCols = rows
+	}
+	for i := 0; i < int(t%4); i++ {
+		r, c = newCols-1-c, r
+		newCols = rows + cols - newCols
+	}
+	if t >= 4 {
+		c = cols - 1 - c
+	}
+	return [2]int{r, c}
+}
+
+// Apply the transform to a path on a rows x cols board
+func (t Transform) Path(path [][2]int, rows, cols int) [][2]int {
+	transformed := make([][2]int, len(path))
+	for i, cell := range path {
+		transformed[i] = t.Cell(cell, rows, cols)
+	}
+	return transformed
+}
+
+// Map a path on the transformed board back onto the rows x cols board
+func (t Transform) InversePath(path [][2]int, rows, cols int) [][2]int {
+	original := make([][2]int, len(path))
+	for i, cell := range path {
+		original[i] = t.InverseCell(cell, rows, cols)
+	}
+	return original
+}
+
+// Canonical form of a board, the smallest of its 8 transforms, and the transform that gives it.
+// Boards are ordered by rows, then row by row, so boards that are rotations or mirrors
+// of each other share the same canonical form.
+func CanonicalBoard(board [][]int) ([][]int, Transform) {
+	best, bestTransform := board, Transform(0)
+	for t := Transform(1); t < 8; t++ {
+		transformed := t.Board(board)
+		if compareBoards(transformed, best) < 0 {
+			best, bestTransform = transformed, t
+		}
+	}
+	return best, bestTransform
+}
+
+// Compare two boards, first by their amount of rows then cell by cell
+func compareBoards(a, b [][]int) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	for r := range a {
+		for c := range a[r] {
+			if a[r][c] != b[r][c] {
+				return a[r][c] - b[r][c]
+			}
+		}
+	}
+	return 0
+}
+
+// Stable hash of the canonical form of a board, as hex.
+// The board must be rectangular and not empty.
+func BoardHash(board [][]int) (string, Transform) {
+	canonical, t := CanonicalBoard(board)
+
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(len(canonical)))
+	sb.WriteByte('x')
+	sb.WriteString(strconv.Itoa(len(canonical[0])))
+	for _, row := range canonical {
+		sb.WriteByte(':')
+		for _, cell := range row {
+			sb.WriteString(strconv.Itoa(cell))
+		}
+	}
+
+	sum := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(sum[:]), t
+}
+
+// Key of a solution kept by a SolutionStore, the canonical hash of the board and the solver that solved it.
+// Solvers are kept apart so comparing them never returns the work of another one.
+type SolutionKey struct {
+	Hash      string
+	Algorithm string
+}
+
+// Solution of a board kept by a SolutionStore, with the path on the canonical board
+type StoredSolution struct {
+	Path      [][2]int // Empty when the board has no solution
+	Status    Status   // StatusFound or StatusNotFound
+	Reason    string   // Why the board has no solution, if it was rejected without searching
+	Algorithm string   // Solver that produced it
+	TimeNs    int64    // Time the solver took
+	Stats     Stats    // Statistics of the run that produced it
+}
+
+// SolutionStore keeps the solutions of boards by canonical hash and solver
+type SolutionStore interface {
+	Load(key SolutionKey) (StoredSolution, bool)
+	Save(key SolutionKey, solution StoredSolution)
+}
+
+// In-memory SolutionStore holding at most size solutions, the oldest one is dropped first
+type MemoryStore struct {
+	mu      sync.Mutex
+	size    int
+	entries map[SolutionKey]StoredSolution
+	order   []SolutionKey
+}
+
+// Create a MemoryStore of at most size solutions
+func NewMemoryStore(size int) *MemoryStore {
+	return &MemoryStore{size: size, entries: make(map[SolutionKey]StoredSolution)}
+}
+
+func (m *MemoryStore) Load(key SolutionKey) (StoredSolution, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	solution, ok := m.entries[key]
+	return solution, ok
+}
+
+func (m *MemoryStore) Save(key SolutionKey, solution StoredSolution) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries[key]; !ok {
+		if len(m.order) >= m.size {
+			delete(m.entries, m.order[0])
+			m.order = m.order[1:]
+		}
+		m.order = append(m.order, key)
+	}
+	m.entries[key] = solution
+}
+
+// SolutionStore made of several stores, looked up in order.
+// A solution found in a later store is copied into the earlier ones, and saves go to every store.
+type StoreChain []SolutionStore
+
+func (chain StoreChain) Load(key SolutionKey) (StoredSolution, bool) {
+	for i, store := range chain {
+		if solution, ok := store.Load(key); ok {
+			for _, earlier := range chain[:i] {
+				earlier.Save(key, solution)
+			}
+			return solution, true
+		}
+	}
+	return StoredSolution{}, false
+}
+
+func (chain StoreChain) Save(key SolutionKey, solution StoredSolution) {
+	for _, store := range chain {
+		store.Save(key, solution)
+	}
+}
+
+// Solve a board with solver, unless store already knows the result of solver on its canonical form.
+// A known path is mapped back onto the board, and found or proven unsolvable boards are saved.
+// Searches continuing a prefix are never cached.
+// Also returns the run the result comes from, with the solver and the time it took, loaded from the store
+// or measured now, and tells if the result came from the store.
+func SolveCached(ctx context.Context, store SolutionStore, solver Solver, board [][]int, opts Options) (Result, StoredSolution, bool) {
+	if len(opts.Prefix) > 0 || len(board) == 0 || len(board[0]) == 0 || !isRectangular(board) {
+		start := time.Now()
+		result := solver.Solve(ctx, board, opts)
+		return result, StoredSolution{Algorithm: solver.Name(), TimeNs: time.Since(start).Nanoseconds()}, false
+	}
+
+	rows, cols := len(board), len(board[0])
+	hash, t := BoardHash(board)
+	key := SolutionKey{Hash: hash, Algorithm: solver.Name()}
+	if stored, ok := store.Load(key); ok {
+		result := Result{Status: stored.Status, Reason: stored.Reason, Stats: stored.Stats}
+		if stored.Status == StatusFound {
+			result.Path = t.InversePath(stored.Path, rows, cols)
+		}
+		return result, stored, true
+	}
+
+	start := time.Now()
+	result := solver.Solve(ctx, board, opts)
+	solution := StoredSolution{
+		Path:      t.Path(result.Path, rows, cols),
+		Status:    result.Status,
+		Reason:    result.Reason,
+		Algorithm: solver.Name(),
+		TimeNs:    time.Since(start).Nanoseconds(),
+		Stats:     result.Stats,
+	}
+	if result.Status == StatusFound || result.Status == StatusNotFound {
+		store.Save(key, solution)
+	}
+
+	return result, solution, false
+}
+
+// Check if every row of the board has the same length
+func isRectangular(board [][]int) bool {
+	for _, row := range board {
+		if len(row) != len(board[0]) {
+			return false
+		}
+	}
+	return true
+}
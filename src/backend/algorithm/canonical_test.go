@@ -0,0 +1,135 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTransformsRoundTrip(t *testing.T) {
+	board := [][]int{
+		{2, 0, 0, 1},
+		{0, 1, 0, 0},
+		{0, 0, 0, 0},
+	}
+	path := [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {2, 3}, {1, 3}, {1, 2}, {0, 2}, {0, 1}}
+	if err := VerifyPath(board, path); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	hash, _ := BoardHash(board)
+	for tr := Transform(0); tr < 8; tr++ {
+		transformed := tr.Board(board)
+		seen[fmt.Sprint(transformed)] = true
+
+		if err := VerifyPath(transformed, tr.Path(path, 3, 4)); err != nil {
+			t.Errorf("transform %d: path does not follow the board: %v", tr, err)
+		}
+		if back := tr.InversePath(tr.Path(path, 3, 4), 3, 4); !reflect.DeepEqual(back, path) {
+			t.Errorf("transform %d: path came back as %v", tr, back)
+		}
+		if other, _ := BoardHash(transformed); other != hash {
+			t.Errorf("transform %d: hash %s, want %s", tr, other, hash)
+		}
+	}
+	if len(seen) != 8 {
+		t.Errorf("got %d distinct transforms, want 8", len(seen))
+	}
+
+	mirrored := [][]int{{2, 0, 1}, {0, 0, 0}}
+	if h, _ := BoardHash(mirrored); h == hash {
+		t.Errorf("different boards share the hash %s", h)
+	}
+}
+
+func TestSolveCachedMapsPathBack(t *testing.T) {
+	store := NewMemoryStore(10)
+	board := [][]int{
+		{2, 0, 0, 1},
+		{0, 1, 0, 0},
+		{0, 0, 0, 0},
+	}
+
+	if _, _, cached := SolveCached(context.Background(), store, dfsSolver{}, board, Options{}); cached {
+		t.Fatal("empty store returned a result")
+	}
+
+	for tr := Transform(0); tr < 8; tr++ {
+		transformed := tr.Board(board)
+		result, _, cached := SolveCached(context.Background(), store, dfsSolver{}, transformed, Options{})
+		if !cached || !result.Found() {
+			t.Fatalf("transform %d: cached %v, status %s", tr, cached, result.Status)
+		}
+		if err := VerifyPath(transformed, result.Path); err != nil {
+			t.Errorf("transform %d: cached path does not solve the board: %v", tr, err)
+		}
+	}
+
+	unsolvable := [][]int{{2, 1, 0}}
+	SolveCached(context.Background(), store, dfsSolver{}, unsolvable, Options{})
+	if result, _, cached := SolveCached(context.Background(), store, dfsSolver{}, unsolvable, Options{}); !cached || result.Status != StatusNotFound {
+		t.Errorf("unsolvable board: cached %v, status %s", cached, result.Status)
+	}
+}
+
+func TestSolveCachedKeepsSolverRuns(t *testing.T) {
+	store := NewMemoryStore(10)
+	board := [][]int{
+		{2, 0, 0, 1},
+		{0, 1, 0, 0},
+		{0, 0, 0, 0},
+	}
+
+	first, firstRun, _ := SolveCached(context.Background(), store, dfsSolver{}, board, Options{})
+	result, run, cached := SolveCached(context.Background(), store, dfsSolver{}, board, Options{})
+	if !cached {
+		t.Fatal("repeated solve was not cached")
+	}
+	if run.Algorithm != "dfs" || run.TimeNs <= 0 || run.TimeNs != firstRun.TimeNs {
+		t.Errorf("cached solve of %q in %dns, want dfs in %dns", run.Algorithm, run.TimeNs, firstRun.TimeNs)
+	}
+	if result.Stats != first.Stats {
+		t.Errorf("cached stats %+v, want %+v", result.Stats, first.Stats)
+	}
+
+	// Another solver never gets the result of dfs
+	if _, run, cached := SolveCached(context.Background(), store, greedySolver{}, board, Options{}); cached || run.Algorithm != "greed" {
+		t.Errorf("greed got the cached result of %s", run.Algorithm)
+	}
+}
+
+func TestMemoryStoreDropsOldest(t *testing.T) {
+	store := NewMemoryStore(2)
+	a, b, c := SolutionKey{"a", "dfs"}, SolutionKey{"b", "dfs"}, SolutionKey{"c", "dfs"}
+	store.Save(a, StoredSolution{Status: StatusFound})
+	store.Save(b, StoredSolution{Status: StatusFound})
+	store.Save(c, StoredSolution{Status: StatusFound})
+
+	if _, ok := store.Load(a); ok {
+		t.Error("oldest solution kept")
+	}
+	if _, ok := store.Load(c); !ok {
+		t.Error("newest solution dropped")
+	}
+}
+
+func TestStoreChainCopiesIntoEarlierStores(t *testing.T) {
+	memory, persistent := NewMemoryStore(10), NewMemoryStore(10)
+	a, b := SolutionKey{"a", "dfs"}, SolutionKey{"b", "dfs"}
+	persistent.Save(a, StoredSolution{Status: StatusNotFound, Algorithm: "dfs"})
+	chain := StoreChain{memory, persistent}
+
+	if solution, ok := chain.Load(a); !ok || solution.Algorithm != "dfs" {
+		t.Fatalf("chain did not find the solution of the last store")
+	}
+	if _, ok := memory.Load(a); !ok {
+		t.Error("solution was not copied into the first store")
+	}
+
+	chain.Save(b, StoredSolution{Status: StatusFound})
+	if _, ok := persistent.Load(b); !ok {
+		t.Error("save did not reach the last store")
+	}
+}
@@ -1,32 +1,96 @@
 package algorithm
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
+
+// Solver using DFS over the board graph
+type dfsSolver struct{}
+
+func init() {
+	Register(dfsSolver{})
+}
+
+func (dfsSolver) Name() string {
+	return "dfs"
+}
+
+func (dfsSolver) Solve(ctx context.Context, board [][]int, opts Options) Result {
+	graph, startID, err := BoardToGraph(board)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	return DFS(ctx, graph, startID, opts)
+}
 
 // DFS algorithm
-func DFS(graph *Graph, startID int) ([][2]int, bool) {
-	fmt.Println("[DFS] starting algorithm")
+func DFS(ctx context.Context, graph *Graph, startID int, opts Options) Result {
+	fmt.Fprintln(logOutput, "[DFS] starting algorithm")
 
+	prefix, err := graph.startingPath(startID, opts.Prefix)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	// Continue the search from the last node of the prefix
 	visited := make([]bool, len(graph.Nodes))
-	var path [][2]int
+	for _, id := range prefix[:len(prefix)-1] {
+		visited[id] = true
+	}
+	path := idsToPath(graph, prefix[:len(prefix)-1])
 
-	result, found := DFSRecursive(graph, startID, visited, &path)
+	s := newSearch(ctx, opts)
+	s.usePruner(len(graph.Nodes))
+	s.useTranspositions()
+	for _, id := range prefix[:len(prefix)-1] {
+		s.toggleVisited(id)
+	}
 
-	return result, found
+	result, found := DFSRecursive(s, graph, prefix[len(prefix)-1], visited, &path)
+
+	return s.result(result, found)
 }
 
-func DFSRecursive(graph *Graph, currentID int, visited []bool, path *[][2]int) ([][2]int, bool) {
+func DFSRecursive(s *search, graph *Graph, currentID int, visited []bool, path *[][2]int) ([][2]int, bool) {
+	if !s.expand(len(*path) + 1) {
+		return nil, false
+	}
+
 	visited[currentID] = true
-	*path = append(*path, [2]int{graph.Nodes[currentID].X, graph.Nodes[currentID].Y})
+	s.toggleVisited(currentID)
+	cell := [2]int{graph.Nodes[currentID].X, graph.Nodes[currentID].Y}
+	*path = append(*path, cell)
+	s.tracePush(cell, len(*path))
 
 	// Check if all nodes are visited
 	if len(*path) == len(graph.Nodes) {
 		return *path, true
 	}
 
+	// Stop if the same state was already explored, or if the unvisited nodes can no longer be completed
+	if s.knownDead(currentID) {
+		visited[currentID] = false
+		s.toggleVisited(currentID)
+		*path = (*path)[:len(*path)-1]
+		s.traceBacktrack(cell, len(*path))
+		return nil, false
+	}
+	if s.pruner != nil && s.pruner.graphDeadEnd(graph, visited, currentID, len(graph.Nodes)-len(*path)) {
+		s.stats.Pruned++
+		s.markDead(currentID)
+		visited[currentID] = false
+		s.toggleVisited(currentID)
+		*path = (*path)[:len(*path)-1]
+		s.traceBacktrack(cell, len(*path))
+		return nil, false
+	}
+
 	// Explore neighbors
-	for _, neighbor := range graph.Edges[currentID] {
+	for _, neighbor := range graph.Edges(currentID) {
 		if !visited[neighbor] {
-			resultPath, found := DFSRecursive(graph, neighbor, visited, path)
+			resultPath, found := DFSRecursive(s, graph, neighbor, visited, path)
 			if found {
 				return resultPath, true
 			}
@@ -34,8 +98,12 @@ func DFSRecursive(graph *Graph, currentID int, visited []bool, path *[][2]int) (
 	}
 
 	// Backtrack
+	s.stats.Backtracks++
+	s.markDead(currentID)
 	visited[currentID] = false
+	s.toggleVisited(currentID)
 	*path = (*path)[:len(*path)-1]
+	s.traceBacktrack(cell, len(*path))
 
 	return nil, false
 }
@@ -0,0 +1,156 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Solver using DFS with an explicit stack, for boards too large to recurse over
+type iterativeDFSSolver struct{}
+
+func init() {
+	Register(iterativeDFSSolver{})
+}
+
+func (iterativeDFSSolver) Name() string {
+	return "dfsiter"
+}
+
+func (iterativeDFSSolver) Solve(ctx context.Context, board [][]int, opts Options) Result {
+	graph, startID, err := BoardToGraph(board)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	return IterativeDFS(ctx, graph, startID, opts)
+}
+
+// Frame of the explicit stack: a node of the path, its edges and the index of the next edge to try
+type dfsFrame struct {
+	id    int
+	edges []int
+	next  int
+}
+
+// State of an iterative DFS, every buffer is sized to the graph once
+type iterativeDFS struct {
+	s       *search
+	graph   *Graph
+	visited []bool
+	path    []int
+	stack   []dfsFrame
+}
+
+// DFS algorithm without recursion.
+// Explores the neighbours in the same order as DFS, so both return the same path.
+func IterativeDFS(ctx context.Context, graph *Graph, startID int, opts Options) Result {
+	fmt.Fprintln(logOutput, "[IterativeDFS] starting algorithm")
+
+	prefix, err := graph.startingPath(startID, opts.Prefix)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	numNodes := len(graph.Nodes)
+	d := &iterativeDFS{
+		s:       newSearch(ctx, opts),
+		graph:   graph,
+		visited: make([]bool, numNodes),
+		path:    make([]int, 0, numNodes),
+		stack:   make([]dfsFrame, 0, numNodes),
+	}
+	d.s.usePruner(numNodes)
+	d.s.useTranspositions()
+
+	// Continue the search from the last node of the prefix
+	for _, id := range prefix[:len(prefix)-1] {
+		d.visited[id] = true
+		d.s.toggleVisited(id)
+		d.path = append(d.path, id)
+	}
+
+	if d.run(prefix[len(prefix)-1]) {
+		return d.s.result(idsToPath(graph, d.path), true)
+	}
+	return d.s.result(nil, false)
+}
+
+// Search from the node first, returns true once the path holds every node
+func (d *iterativeDFS) run(first int) bool {
+	s, numNodes := d.s, len(d.graph.Nodes)
+
+	next := first
+	for {
+		if next >= 0 {
+			id := next
+			next = -1
+
+			if !s.expand(len(d.path) + 1) {
+				return false
+			}
+			d.push(id)
+
+			// Check if all nodes are visited
+			if len(d.path) == numNodes {
+				return true
+			}
+
+			// Go back if the same state was already explored, or if the unvisited nodes can no longer be completed
+			if s.knownDead(id) {
+				d.pop()
+			} else if s.pruner != nil && s.pruner.graphDeadEnd(d.graph, d.visited, id, numNodes-len(d.path)) {
+				s.stats.Pruned++
+				s.markDead(id)
+				d.pop()
+			} else {
+				d.stack = append(d.stack, dfsFrame{id: id, edges: d.graph.Edges(id)})
+			}
+		}
+
+		if len(d.stack) == 0 {
+			return false
+		}
+
+		// Explore the next unvisited neighbour of the last node
+		top := &d.stack[len(d.stack)-1]
+		for top.next < len(top.edges) && d.visited[top.edges[top.next]] {
+			top.next++
+		}
+		if top.next < len(top.edges) {
+			next = top.edges[top.next]
+			top.next++
+			continue
+		}
+
+		// Backtrack
+		s.stats.Backtracks++
+		s.markDead(top.id)
+		d.stack = d.stack[:len(d.stack)-1]
+		d.pop()
+	}
+}
+
+// Add a node to the path
+func (d *iterativeDFS) push(id int) {
+	d.visited[id] = true
+	d.s.toggleVisited(id)
+	d.path = append(d.path, id)
+	if d.s.trace != nil {
+		d.s.tracePush(d.cell(id), len(d.path))
+	}
+}
+
+// Remove the last node of the path
+func (d *iterativeDFS) pop() {
+	id := d.path[len(d.path)-1]
+	d.visited[id] = false
+	d.s.toggleVisited(id)
+	d.path = d.path[:len(d.path)-1]
+	if d.s.trace != nil {
+		d.s.traceBacktrack(d.cell(id), len(d.path))
+	}
+}
+
+func (d *iterativeDFS) cell(id int) [2]int {
+	return [2]int{d.graph.Nodes[id].X, d.graph.Nodes[id].Y}
+}
@@ -0,0 +1,98 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestIterativeDFSMatchesDFS(t *testing.T) {
+	boards := randomTestBoards(11, 500)
+	for _, level := range Levels() {
+		generated, _ := generateBoard(level.Rows, level.Columns, level.freeCells(), rand.New(rand.NewSource(1)))
+		boards = append(boards, generated.Board)
+	}
+
+	for _, board := range boards {
+		graph, startID, err := BoardToGraph(board)
+		if err != nil {
+			continue
+		}
+
+		for _, opts := range []Options{
+			{MaxNodes: testNodeBudget},
+			{MaxNodes: testNodeBudget, DisablePruning: true},
+			{MaxNodes: testNodeBudget, TranspositionSize: testTranspositionSize},
+		} {
+			want := DFS(context.Background(), graph, startID, opts)
+			got := IterativeDFS(context.Background(), graph, startID, opts)
+
+			if got.Status != want.Status || !reflect.DeepEqual(got.Path, want.Path) {
+				t.Fatalf("board %v: iterative %s %v, recursive %s %v", board, got.Status, got.Path, want.Status, want.Path)
+			}
+			// The recursive search still counts backtracks while it unwinds after the budget ran out
+			got.Stats.TimeNs, want.Stats.TimeNs = 0, 0
+			if want.Status != StatusTimedOut && got.Stats != want.Stats {
+				t.Errorf("board %v: iterative stats %+v, recursive %+v", board, got.Stats, want.Stats)
+			}
+		}
+	}
+}
+
+func TestIterativeDFSContinuesPrefix(t *testing.T) {
+	board := [][]int{
+		{2, 0, 0},
+		{0, 0, 0},
+		{0, 0, 0},
+	}
+	graph, startID, _ := BoardToGraph(board)
+
+	result := IterativeDFS(context.Background(), graph, startID, Options{Prefix: [][2]int{{0, 0}, {1, 0}, {1, 1}}})
+	if !result.Found() {
+		t.Fatalf("no path found: %s", result.Status)
+	}
+	if err := VerifyPath(board, result.Path); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Path[:3], [][2]int{{0, 0}, {1, 0}, {1, 1}}) {
+		t.Errorf("path %v does not continue the prefix", result.Path)
+	}
+}
+
+func BenchmarkIterativeDFS(b *testing.B) {
+	boards := map[string][][]int{
+		"testEasy":   loadTestBoard(b, "testEasy"),
+		"testMedium": loadTestBoard(b, "testMedium"),
+	}
+	for _, size := range []int{30, 40} {
+		generated, _ := generateBoard(size, size, size*size*85/100, rand.New(rand.NewSource(1)))
+		boards[fmt.Sprintf("%dx%d", size, size)] = generated.Board
+	}
+
+	for name, board := range boards {
+		graph, startID, err := BoardToGraph(board)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, bench := range []struct {
+			name  string
+			solve func(context.Context, *Graph, int, Options) Result
+		}{
+			{"iterative", IterativeDFS},
+			{"recursive", DFS},
+		} {
+			for _, prune := range []bool{true, false} {
+				opts := Options{MaxNodes: 20000, DisablePruning: !prune}
+				b.Run(fmt.Sprintf("%s/%s/pruned=%v", name, bench.name, prune), func(b *testing.B) {
+					b.ReportAllocs()
+					for i := 0; i < b.N; i++ {
+						bench.solve(context.Background(), graph, startID, opts)
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Node budget of each search done to rate a board, keeps the rating deterministic
+const ratingNodeBudget = 500000
+
+// Options of every search done to rate a board: the node budget and no memo, whose slots would
+// change the amount of nodes expanded, so a board gets the same rating wherever it is rated
+var ratingOptions = Options{MaxNodes: ratingNodeBudget}
+
+// Solutions counted when rating a board, more than this is rated like this many
+const ratingSolutionCap = 20
+
+// Difficulty tiers, from the lowest score
+var difficultyTiers = []struct {
+	name     string
+	maxScore float64
+}{
+	{"beginner", 20},
+	{"easy", 40},
+	{"medium", 60},
+	{"hard", 80},
+	{"expert", math.Inf(1)},
+}
+
+// Returned with the closest candidate when no generated board fell inside the difficulty band in time
+var ErrDifficultyNotReached = errors.New("no board inside the difficulty band was found in time")
+
+// Range of difficulty scores, Min included and Max excluded
+type DifficultyBand struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Check if a score falls inside the band
+func (b DifficultyBand) Contains(score float64) bool {
+	return score >= b.Min && score < b.Max
+}
+
+// Distance of a score to the band, 0 inside it
+func (b DifficultyBand) distance(score float64) float64 {
+	return math.Max(math.Max(b.Min-score, score-b.Max), 0)
+}
+
+// Band of scores of a difficulty tier
+func TierBand(tier string) (DifficultyBand, error) {
+	low := 0.0
+	for _, t := range difficultyTiers {
+		if t.name == tier {
+			return DifficultyBand{Min: low, Max: t.maxScore}, nil
+		}
+		low = t.maxScore
+	}
+	return DifficultyBand{}, fmt.Errorf("invalid tier: %s", tier)
+}
+
+// Difficulty of a board, estimated from how hard it is to solve
+type Difficulty struct {
+	Score           float64 `json:"score"` // From 0 (trivial) to 100, 0 when the tier is unknown
+	Tier            string  `json:"tier"`
+	Solvable        *bool   `json:"solvable"`        // Null when no solution was found within the budget
+	NodesExpanded   int64   `json:"nodesExpanded"`   // Effort of the DFS solver
+	EffortCapped    bool    `json:"effortCapped"`    // The DFS ran out of budget, the effort is at least NodesExpanded
+	ForcedMoves     int     `json:"forcedMoves"`     // Moves along the solution where only one dot was free
+	Branching       float64 `json:"branching"`       // Average amount of free dots along the solution
+	Solutions       int     `json:"solutions"`       // Capped at ratingSolutionCap
+	SolutionsCapped bool    `json:"solutionsCapped"` // The count ran out of budget, there are at least Solutions
+	Reason          string  `json:"reason,omitempty"`
+}
+
+// Rate the difficulty of a board, solution is a known solution of it or nil.
+// Every search runs with ratingOptions and the measures along the solution use the one DFS finds,
+// or Greedy when DFS runs out of budget, so the rating only depends on the board and not on
+// how it was made or on the budget of the request. The known solution only stands in when
+// neither finds one, without it the board is rated unknown.
+func RateDifficulty(ctx context.Context, board [][]int, solution [][2]int) Difficulty {
+	graph, startID, err := BoardToGraph(board)
+	if err != nil {
+		return Difficulty{Tier: "unsolvable", Solvable: new(bool), Reason: err.Error()}
+	}
+
+	result := DFS(ctx, graph, startID, ratingOptions)
+	d := Difficulty{
+		NodesExpanded: result.Stats.NodesExpanded,
+		EffortCapped:  result.Status == StatusTimedOut,
+	}
+	if result.Status == StatusNotFound {
+		return Difficulty{Tier: "unsolvable", Solvable: new(bool), Reason: "no solution found"}
+	}
+	if result.Found() {
+		solution = result.Path
+	} else if greedy := Greedy(ctx, board, ratingOptions); greedy.Found() {
+		solution = greedy.Path
+	}
+	if solution == nil {
+		d.Tier = "unknown"
+		d.Reason = "no solution found within the rating budget"
+		return d
+	}
+	solvable := true
+	d.Solvable = &solvable
+
+	// Free dots at every step of the solution
+	b := NewBitBoard(board)
+	choices := 0
+	for _, cell := range solution[:len(solution)-1] {
+		b.Visit(b.Index(cell[0], cell[1]))
+		free := b.Connections(b.Index(cell[0], cell[1]))
+		if free == 1 {
+			d.ForcedMoves++
+		}
+		choices += free
+	}
+
+	moves := len(solution) - 1
+	if moves > 0 {
+		d.Branching = float64(choices) / float64(moves)
+	}
+
+	count := CountSolutions(ctx, board, ratingSolutionCap, ratingOptions)
+	d.Solutions = max(count.Count, 1)
+	d.SolutionsCapped = count.TimedOut
+
+	d.Score = difficultyScore(d, len(solution))
+	d.Tier = tierOf(d.Score)
+
+	return d
+}
+
+// Combine the measures of a solvable board of dots usable dots into a score from 0 to 100
+func difficultyScore(d Difficulty, dots int) float64 {
+	// Nodes expanded per dot, 1 when the DFS walks straight to the solution
+	effort := math.Min(math.Log2(float64(d.NodesExpanded)/float64(dots)+1)/10, 1)
+	if d.EffortCapped {
+		effort = 1
+	}
+
+	freedom := 0.0
+	if dots > 1 {
+		freedom = 1 - float64(d.ForcedMoves)/float64(dots-1)
+	}
+	branching := math.Min(math.Max((d.Branching-1)/1.5, 0), 1)
+
+	// A count cut short by the budget says nothing about how unique the solution is
+	uniqueness := 0.0
+	if !d.SolutionsCapped {
+		uniqueness = 1 / float64(d.Solutions)
+	}
+
+	score := 100 * (0.4*effort + 0.25*freedom + 0.15*branching + 0.2*uniqueness)
+	return math.Round(score*10) / 10
+}
+
+// Name of the tier of a score
+func tierOf(score float64) string {
+	for _, tier := range difficultyTiers {
+		if score < tier.maxScore {
+			return tier.name
+		}
+	}
+	return difficultyTiers[len(difficultyTiers)-1].name
+}
+
+// Generate a solvable board of the level whose difficulty falls inside band.
+// Candidates come from the seeds following seed and are rated one after another
+// until one fits, or until maxTime has passed or ctx is done. In the latter case the
+// closest fully rated candidate is returned together with ErrDifficultyNotReached.
+func GenerateBoardWithDifficulty(ctx context.Context, level string, band DifficultyBand, seed int64, maxTime time.Duration) (*GeneratedBoard, error) {
+	ctx, cancel := context.WithTimeout(ctx, maxTime)
+	defer cancel()
+
+	var closest *GeneratedBoard
+	for candidateSeed := seed; ; candidateSeed++ {
+		generated, err := GenerateSeededBoard(level, candidateSeed)
+		if err != nil {
+			return nil, err
+		}
+		generated.Rate(ctx)
+
+		// A rating cut short by ctx is not the rating of the board
+		if ctx.Err() != nil {
+			if closest == nil {
+				return nil, fmt.Errorf("no board rated in time: %w", ctx.Err())
+			}
+			return closest, ErrDifficultyNotReached
+		}
+
+		score := generated.Difficulty.Score
+		if band.Contains(score) {
+			return generated, nil
+		}
+		if closest == nil || band.distance(score) < band.distance(closest.Difficulty.Score) {
+			closest = generated
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+)
+
+// Largest amount of usable dots the bitmask DP accepts, its table has 2^(dots-1) entries of 4 bytes
+const MaxDPDots = 25
+
+// Solver using the bitmask dynamic programming
+type dpSolver struct{}
+
+func init() {
+	Register(dpSolver{})
+}
+
+func (dpSolver) Name() string {
+	return "dp"
+}
+
+func (dpSolver) Solve(ctx context.Context, board [][]int, opts Options) Result {
+	graph, startID, err := buildGraph(board)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	return DP(ctx, graph, startID, opts)
+}
+
+// DP algorithm, Held-Karp style dynamic programming over (visited dots, current dot).
+// Always gives an exact answer, in time and memory that only depend on the amount of dots,
+// so it is limited to boards of at most MaxDPDots usable dots.
+// It does not rely on any pre-check or pruning, which makes it a reference for the other solvers.
+// There is no single path while it searches, so its trace holds a layer event for every amount
+// of dots the paths reach, then the pushes of the solution if it finds one.
+func DP(ctx context.Context, graph *Graph, startID int, opts Options) Result {
+	fmt.Fprintln(logOutput, "[DP] starting algorithm")
+
+	n := len(graph.Nodes)
+	if n > MaxDPDots {
+		return Result{
+			Status: StatusUnsupported,
+			Reason: fmt.Sprintf("%d usable dots, the bitmask DP supports at most %d", n, MaxDPDots),
+		}
+	}
+
+	prefix, err := graph.startingPath(startID, opts.Prefix)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	s := newSearch(ctx, opts)
+	if len(prefix) == n {
+		return s.result(idsToPath(graph, prefix), true)
+	}
+
+	// The starting dot is always visited, so it is left out of the masks.
+	// Bit i stands for node i, or node i+1 for the nodes after the starting dot.
+	nodeOf := func(bit int) int {
+		if bit >= startID {
+			return bit + 1
+		}
+		return bit
+	}
+	bitOf := func(id int) int {
+		if id > startID {
+			return id - 1
+		}
+		return id
+	}
+
+	// Neighbours of every bit as a mask
+	adjacent := make([]uint32, n-1)
+	for bit := range adjacent {
+		for _, neighbor := range graph.Edges(nodeOf(bit)) {
+			if neighbor != startID {
+				adjacent[bit] |= 1 << bitOf(neighbor)
+			}
+		}
+	}
+
+	// ends[mask] holds every bit a path from the starting dot over exactly the dots of mask can end in,
+	// only paths beginning with the prefix are considered
+	full := uint32(1)<<(n-1) - 1
+	ends := make([]uint32, full+1)
+	if len(prefix) == 1 {
+		for _, neighbor := range graph.Edges(startID) {
+			ends[1<<bitOf(neighbor)] |= 1 << bitOf(neighbor)
+		}
+	} else {
+		var prefixMask uint32
+		for _, id := range prefix[1:] {
+			prefixMask |= 1 << bitOf(id)
+		}
+		ends[prefixMask] = 1 << bitOf(prefix[len(prefix)-1])
+	}
+
+	// Extend the paths layer by layer, a layer holds every mask of the same amount of dots
+	for size := max(len(prefix)-1, 1); size < n-1; size++ {
+		states := 0
+		for mask := uint32(1)<<size - 1; mask < full; mask = nextMask(mask) {
+			for last := ends[mask]; last != 0; last &= last - 1 {
+				if !s.expand(size + 1) {
+					return s.result(nil, false)
+				}
+				states++
+
+				bit := bits.TrailingZeros32(last)
+				for next := adjacent[bit] &^ mask; next != 0; next &= next - 1 {
+					nextBit := uint32(1) << bits.TrailingZeros32(next)
+					ends[mask|nextBit] |= nextBit
+				}
+			}
+		}
+		s.traceLayer(size+1, states)
+	}
+
+	if ends[full] == 0 {
+		return s.result(nil, false)
+	}
+
+	// Walk the table backwards from any end of a full path until the prefix
+	ids := make([]int, n)
+	copy(ids, prefix)
+	mask := full
+	bit := bits.TrailingZeros32(ends[full])
+	for i := n - 1; i >= len(prefix); i-- {
+		ids[i] = nodeOf(bit)
+		mask &^= 1 << bit
+		if i > len(prefix) {
+			bit = bits.TrailingZeros32(ends[mask] & adjacent[bit])
+		}
+	}
+
+	path := idsToPath(graph, ids)
+	for i, cell := range path {
+		s.tracePush(cell, i+1)
+	}
+
+	return s.result(path, true)
+}
+
+// Next mask with the same amount of bits set, in increasing order (Gosper's hack)
+func nextMask(mask uint32) uint32 {
+	low := mask & -mask
+	ripple := mask + low
+	return ripple | (mask^ripple)>>2/low
+}
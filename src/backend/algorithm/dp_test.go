@@ -0,0 +1,78 @@
+package algorithm
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// Random boards of 2x2 up to 5x5 cells, small enough for every solver including the DP
+func randomTestBoards(seed int64, count int) [][][]int {
+	rng := rand.New(rand.NewSource(seed))
+	boards := make([][][]int, count)
+
+	for i := range boards {
+		rows, cols := 2+rng.Intn(4), 2+rng.Intn(4)
+		board := make([][]int, rows)
+		for r := range board {
+			board[r] = make([]int, cols)
+			for c := range board[r] {
+				if rng.Intn(5) == 0 {
+					board[r][c] = 1
+				}
+			}
+		}
+		board[rng.Intn(rows)][rng.Intn(cols)] = 2
+		boards[i] = board
+	}
+
+	return boards
+}
+
+func TestDPFindsKnownPath(t *testing.T) {
+	board := [][]int{
+		{2, 0, 0},
+		{1, 1, 0},
+		{0, 0, 0},
+	}
+
+	result := dpSolver{}.Solve(context.Background(), board, Options{})
+	want := [][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 2}, {2, 1}, {2, 0}}
+	if !result.Found() || len(result.Path) != len(want) {
+		t.Fatalf("got %v %v, want %v", result.Status, result.Path, want)
+	}
+	for i := range want {
+		if result.Path[i] != want[i] {
+			t.Fatalf("got %v, want %v", result.Path, want)
+		}
+	}
+}
+
+func TestDPRejectsLargeBoards(t *testing.T) {
+	board := make([][]int, 6)
+	for r := range board {
+		board[r] = make([]int, 6)
+	}
+	board[0][0] = 2
+
+	if result := (dpSolver{}).Solve(context.Background(), board, Options{}); result.Status != StatusUnsupported {
+		t.Fatalf("got %v, want %v", result.Status, StatusUnsupported)
+	}
+}
+
+func TestSolversAgreeWithDP(t *testing.T) {
+	for i, board := range randomTestBoards(1, 300) {
+		want := dpSolver{}.Solve(context.Background(), board, Options{})
+
+		for _, name := range SolverNames() {
+			solver, _ := GetSolver(name)
+			got := solver.Solve(context.Background(), board, Options{})
+
+			if got.Found() != want.Found() {
+				t.Errorf("board %d %v: %s %v, dp %v", i, board, name, got.Status, want.Status)
+			} else if got.Found() && len(got.Path) != len(want.Path) {
+				t.Errorf("board %d %v: %s path of %d dots, dp %d", i, board, name, len(got.Path), len(want.Path))
+			}
+		}
+	}
+}
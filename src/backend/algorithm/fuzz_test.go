@@ -0,0 +1,97 @@
+package algorithm
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Decode fuzz input into a board of at most 6x6, possibly empty.
+// The first bytes give the size and whether the last row is cut short, the following ones the cells,
+// mostly 0, 1 and 2 with an occasional value the game never uses.
+func fuzzBoard(data []byte) [][]int {
+	if len(data) < 3 {
+		return nil
+	}
+	rows, cols := int(data[0])%7, 1+int(data[1])%6
+	ragged := data[2]%4 == 0
+	data = data[3:]
+
+	board := make([][]int, rows)
+	for r := range board {
+		board[r] = make([]int, cols)
+		for c := range board[r] {
+			if len(data) == 0 {
+				break
+			}
+			switch v := data[0] % 16; {
+			case v < 10:
+				board[r][c] = 0
+			case v < 14:
+				board[r][c] = 1
+			case v < 15:
+				board[r][c] = 2
+			default:
+				board[r][c] = 3
+			}
+			data = data[1:]
+		}
+	}
+	if ragged && rows > 0 && cols > 1 {
+		board[rows-1] = board[rows-1][:cols-1]
+	}
+
+	return board
+}
+
+// Encode a board as fuzz input, the inverse of fuzzBoard for well formed boards
+func fuzzInput(board [][]int) []byte {
+	data := []byte{byte(len(board)), byte(len(board[0]) - 1), 1}
+	for _, row := range board {
+		for _, cell := range row {
+			data = append(data, map[int]byte{0: 0, 1: 10, 2: 14}[cell])
+		}
+	}
+	return data
+}
+
+func FuzzSolvers(f *testing.F) {
+	files, _ := filepath.Glob(filepath.Join("..", "..", "..", "test", "*.json"))
+	for _, file := range files {
+		f.Add(fuzzInput(loadTestBoard(f, strings.TrimSuffix(filepath.Base(file), ".json"))))
+	}
+	for _, board := range randomTestBoards(5, 20) {
+		f.Add(fuzzInput(board))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		board := fuzzBoard(data)
+		if board == nil {
+			return
+		}
+
+		PreCheckBoard(board)
+		BoardToGraph(board)
+
+		var found, notFound []string
+		for _, name := range SolverNames() {
+			solver, _ := GetSolver(name)
+			result := solver.Solve(context.Background(), board, Options{MaxNodes: testNodeBudget})
+
+			switch result.Status {
+			case StatusFound:
+				if err := VerifyPath(board, result.Path); err != nil {
+					t.Fatalf("%v: %s returned an invalid path: %v", board, name, err)
+				}
+				found = append(found, name)
+			case StatusNotFound:
+				notFound = append(notFound, name)
+			}
+		}
+
+		if len(found) > 0 && len(notFound) > 0 {
+			t.Fatalf("%v: %v found a path, %v did not", board, found, notFound)
+		}
+	})
+}
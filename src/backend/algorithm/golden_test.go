@@ -0,0 +1,128 @@
+package algorithm
+
+import (
+	"context"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Expected outcome of a solver on a board of test/
+type golden struct {
+	status Status
+	reason string // Empty when any reason is accepted
+}
+
+// Outcome of every solver on every board of test/, within testNodeBudget expanded nodes.
+// Found paths must also pass VerifyPath.
+var goldenOutcomes = map[string]map[string]golden{
+	"testBeginner": {
+		"bf":      {status: StatusFound},
+		"dfs":     {status: StatusFound},
+		"dfsiter": {status: StatusFound},
+		"dp":      {status: StatusFound},
+		"greed":   {status: StatusFound},
+		"main":    {status: StatusFound},
+	},
+	"testEasy": {
+		"bf":      {status: StatusTimedOut},
+		"dfs":     {status: StatusFound},
+		"dfsiter": {status: StatusFound},
+		"dp":      {status: StatusUnsupported},
+		"greed":   {status: StatusFound},
+		"main":    {status: StatusFound},
+	},
+	"testMedium": {
+		"bf":      {status: StatusTimedOut},
+		"dfs":     {status: StatusNotFound},
+		"dfsiter": {status: StatusNotFound},
+		"dp":      {status: StatusUnsupported},
+		"greed":   {status: StatusNotFound},
+		"main":    {status: StatusNotFound},
+	},
+	"testHard": {
+		"bf":      {status: StatusNotFound, reason: "amount of endpoint > 1"},
+		"dfs":     {status: StatusNotFound, reason: "amount of endpoint > 1"},
+		"dfsiter": {status: StatusNotFound, reason: "amount of endpoint > 1"},
+		"dp":      {status: StatusUnsupported},
+		"greed":   {status: StatusNotFound, reason: "amount of endpoint > 1"},
+		"main":    {status: StatusNotFound, reason: "amount of endpoint > 1"},
+	},
+}
+
+func TestGoldenOutcomes(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "test", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test boards")
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			outcomes, ok := goldenOutcomes[name]
+			if !ok {
+				t.Fatalf("no golden outcomes for %s", file)
+			}
+			board := loadTestBoard(t, name)
+
+			for _, solverName := range SolverNames() {
+				want, ok := outcomes[solverName]
+				if !ok {
+					t.Errorf("no golden outcome for %s", solverName)
+					continue
+				}
+
+				solver, _ := GetSolver(solverName)
+				got := solver.Solve(context.Background(), board, Options{MaxNodes: testNodeBudget})
+
+				if got.Status != want.status {
+					t.Errorf("%s: got %v (%s), want %v", solverName, got.Status, got.Reason, want.status)
+				} else if want.reason != "" && got.Reason != want.reason {
+					t.Errorf("%s: got reason %q, want %q", solverName, got.Reason, want.reason)
+				}
+				if got.Found() {
+					if err := VerifyPath(board, got.Path); err != nil {
+						t.Errorf("%s: invalid path: %v", solverName, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSolverPathsKeepPrefix(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	for seed := int64(1); seed <= 20; seed++ {
+		generated, err := GenerateSeededBoard("beginner", seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Any beginning of the known solution can be completed
+		prefix := generated.Solution[:1+rng.Intn(len(generated.Solution))]
+
+		for _, name := range SolverNames() {
+			solver, _ := GetSolver(name)
+			result := solver.Solve(context.Background(), generated.Board, Options{MaxNodes: testNodeBudget, Prefix: prefix})
+
+			if !result.Found() {
+				t.Errorf("seed %d: %s got %v (%s) with a prefix of %d dots", seed, name, result.Status, result.Reason, len(prefix))
+				continue
+			}
+			if err := VerifyPath(generated.Board, result.Path); err != nil {
+				t.Errorf("seed %d: %s returned an invalid path: %v", seed, name, err)
+			}
+			for i := range prefix {
+				if result.Path[i] != prefix[i] {
+					t.Errorf("seed %d: %s path does not begin with the prefix", seed, name)
+					break
+				}
+			}
+		}
+	}
+}
@@ -4,10 +4,16 @@ import (
 	"fmt"
 )
 
-// Graph data structure
+// Graph data structure.
+// Node IDs are dense, from 0 to len(Nodes)-1, and the edges are stored as flat
+// adjacency arrays (CSR): the neighbours of node i are targets[offsets[i]:offsets[i+1]].
 type Graph struct {
-	Nodes map[int]Node
-	Edges map[int][]int
+	Nodes []Node // Node of every ID
+
+	offsets []int
+	targets []int
+
+	grid [][]int // Node ID at every position, -1 where there is none
 }
 
 // Node for graph
@@ -19,85 +25,153 @@ type Node struct {
 
 // Create a new Graph
 func NewGraph() *Graph {
-	return &Graph{
-		Nodes: make(map[int]Node),
-		Edges: make(map[int][]int),
-	}
+	return &Graph{offsets: []int{0}}
 }
 
-// Add a Node to the Graph
+// Add a Node to the Graph. IDs must stay dense, any ID below id that was not added yet
+// is reserved for a later AddNode. Nodes at negative positions are kept but not found by NodeAt.
 func (g *Graph) AddNode(id, x, y int) {
+	g.reserve(id)
 	g.Nodes[id] = Node{ID: id, X: x, Y: y}
+
+	if x < 0 || y < 0 {
+		return
+	}
+	for len(g.grid) <= x {
+		g.grid = append(g.grid, nil)
+	}
+	for len(g.grid[x]) <= y {
+		g.grid[x] = append(g.grid[x], -1)
+	}
+	g.grid[x][y] = id
 }
 
-// Add an Edge between two Nodes
+// Make room for the IDs up to id, the new ones have no edges and wait for their AddNode
+func (g *Graph) reserve(id int) {
+	for len(g.Nodes) <= id {
+		g.Nodes = append(g.Nodes, Node{ID: len(g.Nodes)})
+		g.offsets = append(g.offsets, len(g.targets))
+	}
+}
+
+// Get the ID of the Node at position (x, y)
+func (g *Graph) NodeAt(x, y int) (int, bool) {
+	if x < 0 || x >= len(g.grid) || y < 0 || y >= len(g.grid[x]) || g.grid[x][y] == -1 {
+		return -1, false
+	}
+	return g.grid[x][y], true
+}
+
+// Add an Edge between two Nodes, either of them may be added later
 func (g *Graph) AddEdge(from, to int) {
-	g.Edges[from] = append(g.Edges[from], to)
+	g.reserve(max(from, to))
+
+	// Insert at the end of the edges of from and shift the edges of the next nodes
+	end := g.offsets[from+1]
+	g.targets = append(g.targets, 0)
+	copy(g.targets[end+1:], g.targets[end:])
+	g.targets[end] = to
+	for i := from + 1; i < len(g.offsets); i++ {
+		g.offsets[i]++
+	}
+}
+
+// Neighbours of a Node
+func (g *Graph) Edges(id int) []int {
+	return g.targets[g.offsets[id]:g.offsets[id+1]:g.offsets[id+1]]
 }
 
-// Convert a board to a Graph
+// Convert a board to a Graph, rejecting boards that are known to be unsolvable
 func BoardToGraph(board [][]int) (*Graph, int, error) {
-	rows := len(board)
-	cols := len(board[0])
-	graph := NewGraph()
-	startID := -1
-	nodeID := 0
-	idMap := make([][]int, rows)
-	for i := range idMap {
-		idMap[i] = make([]int, cols)
+	graph, startID, err := buildGraph(board)
+	if err != nil {
+		return nil, -1, err
+	}
+	if err := checkGraph(graph, startID); err != nil {
+		return nil, -1, err
 	}
 
-	// Add nodes
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			if board[r][c] != 1 {
-				if board[r][c] == 2 {
-					startID = nodeID
-				}
-				idMap[r][c] = nodeID
-				graph.AddNode(nodeID, r, c)
-				nodeID++
+	return graph, startID, nil
+}
+
+// Check that the graph is not known to be unsolvable from startID
+func checkGraph(graph *Graph, startID int) error {
+	// Check for isolated nodes or two or more endpoint (causes unsolvable), a lone starting node is already solved
+	countEndPoint := 0
+	for nodeID := range graph.Nodes {
+		if len(graph.Edges(nodeID)) == 0 && len(graph.Nodes) > 1 {
+			return fmt.Errorf("isolated node detected with ID %d", nodeID)
+		}
+		if len(graph.Edges(nodeID)) == 1 && nodeID != startID {
+			countEndPoint++
+			if countEndPoint > 1 {
+				return fmt.Errorf("amount of endpoint > 1")
 			}
 		}
 	}
 
-	// Add edges
+	// Check the checkerboard parity of the nodes
+	var colorCount [2]int
+	for _, node := range graph.Nodes {
+		colorCount[cellColor(node.X, node.Y)]++
+	}
+	start := graph.Nodes[startID]
+	if err := checkParity(colorCount, cellColor(start.X, start.Y)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Convert a board to a Graph without checking if it is solvable
+func buildGraph(board [][]int) (*Graph, int, error) {
+	if _, err := checkBoard(board); err != nil {
+		return nil, -1, err
+	}
+
+	rows := len(board)
+	cols := len(board[0])
+	graph := &Graph{grid: make([][]int, rows)}
+	startID := -1
+
+	// Add nodes, numbered row by row
+	cells := make([]int, rows*cols)
 	for r := 0; r < rows; r++ {
+		graph.grid[r] = cells[r*cols : (r+1)*cols]
 		for c := 0; c < cols; c++ {
+			graph.grid[r][c] = -1
 			if board[r][c] != 1 {
-				currentID := idMap[r][c]
-				// Connect to the right
-				if c < cols-1 && board[r][c+1] != 1 {
-					graph.AddEdge(currentID, idMap[r][c+1])
-				}
-				// Connect to the left
-				if c > 0 && board[r][c-1] != 1 {
-					graph.AddEdge(currentID, idMap[r][c-1])
-				}
-				// Connect to the bottom
-				if r < rows-1 && board[r+1][c] != 1 {
-					graph.AddEdge(currentID, idMap[r+1][c])
-				}
-				// Connect to the top
-				if r > 0 && board[r-1][c] != 1 {
-					graph.AddEdge(currentID, idMap[r-1][c])
+				if board[r][c] == 2 {
+					startID = len(graph.Nodes)
 				}
+				graph.grid[r][c] = len(graph.Nodes)
+				graph.Nodes = append(graph.Nodes, Node{ID: len(graph.Nodes), X: r, Y: c})
 			}
 		}
 	}
 
-	// Check for isolated nodes or two or more endpoint (causes unsolvable)
-	countEndPoint := 0
-	for nodeID := range graph.Nodes {
-		if len(graph.Edges[nodeID]) == 0 {
-			return nil, -1, fmt.Errorf("isolated node detected with ID %d", nodeID)
+	// Add edges, in the same order as the nodes
+	graph.offsets = make([]int, 1, len(graph.Nodes)+1)
+	graph.targets = make([]int, 0, 4*len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		r, c := node.X, node.Y
+		// Connect to the right
+		if c < cols-1 && board[r][c+1] != 1 {
+			graph.targets = append(graph.targets, graph.grid[r][c+1])
 		}
-		if len(graph.Edges[nodeID]) == 1 && nodeID != startID {
-			countEndPoint++
-			if countEndPoint > 1 {
-				return nil, -1, fmt.Errorf("amount of endpoint > 1")
-			}
+		// Connect to the left
+		if c > 0 && board[r][c-1] != 1 {
+			graph.targets = append(graph.targets, graph.grid[r][c-1])
+		}
+		// Connect to the bottom
+		if r < rows-1 && board[r+1][c] != 1 {
+			graph.targets = append(graph.targets, graph.grid[r+1][c])
+		}
+		// Connect to the top
+		if r > 0 && board[r-1][c] != 1 {
+			graph.targets = append(graph.targets, graph.grid[r-1][c])
 		}
+		graph.offsets = append(graph.offsets, len(graph.targets))
 	}
 
 	return graph, startID, nil
@@ -115,7 +189,7 @@ func (g *Graph) PrintGraph() {
 		fmt.Printf("ID: %d, Position: (%d, %d)\n", node.ID, node.X, node.Y)
 	}
 	fmt.Println("Edges:")
-	for from, edges := range g.Edges {
-		fmt.Printf("Node %d -> %v\n", from, edges)
+	for from := range g.Nodes {
+		fmt.Printf("Node %d -> %v\n", from, g.Edges(from))
 	}
 }
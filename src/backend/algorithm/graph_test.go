@@ -0,0 +1,116 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestAddNodeAndAddEdgeMatchBuildGraph(t *testing.T) {
+	board := [][]int{
+		{2, 0, 1},
+		{0, 0, 0},
+	}
+	built, _, err := buildGraph(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add the nodes out of order and the edges interleaved, as a caller of the old map based graph could
+	graph := NewGraph()
+	graph.AddNode(4, 1, 2)
+	graph.AddNode(0, 0, 0)
+	graph.AddNode(2, 1, 0)
+	graph.AddEdge(2, 3)
+	graph.AddNode(1, 0, 1)
+	graph.AddNode(3, 1, 1)
+	for _, edge := range [][2]int{{0, 1}, {4, 3}, {0, 2}, {1, 0}, {3, 4}, {1, 3}, {2, 0}, {3, 2}, {3, 1}} {
+		graph.AddEdge(edge[0], edge[1])
+	}
+
+	if !reflect.DeepEqual(graph.Nodes, built.Nodes) {
+		t.Errorf("nodes %v, want %v", graph.Nodes, built.Nodes)
+	}
+	for id := range built.Nodes {
+		if !reflect.DeepEqual(graph.Edges(id), built.Edges(id)) {
+			t.Errorf("edges of %d: %v, want %v", id, graph.Edges(id), built.Edges(id))
+		}
+		node := built.Nodes[id]
+		if got, ok := graph.NodeAt(node.X, node.Y); !ok || got != id {
+			t.Errorf("NodeAt(%d, %d) = %d, %v, want %d", node.X, node.Y, got, ok, id)
+		}
+	}
+	for _, cell := range [][2]int{{0, 2}, {-1, 0}, {2, 0}, {0, 5}} {
+		if id, ok := graph.NodeAt(cell[0], cell[1]); ok {
+			t.Errorf("NodeAt(%d, %d) = %d, want none", cell[0], cell[1], id)
+		}
+		if id, ok := built.NodeAt(cell[0], cell[1]); ok {
+			t.Errorf("built NodeAt(%d, %d) = %d, want none", cell[0], cell[1], id)
+		}
+	}
+}
+
+func TestAddEdgeBeforeAddNode(t *testing.T) {
+	graph := NewGraph()
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 0)
+	graph.AddNode(0, 0, 0)
+	graph.AddNode(1, -1, 0)
+
+	if len(graph.Nodes) != 2 || !reflect.DeepEqual(graph.Edges(0), []int{1}) || !reflect.DeepEqual(graph.Edges(1), []int{0}) {
+		t.Errorf("nodes %v, edges %v and %v", graph.Nodes, graph.Edges(0), graph.Edges(1))
+	}
+	if graph.Nodes[1] != (Node{ID: 1, X: -1, Y: 0}) {
+		t.Errorf("node 1 is %v", graph.Nodes[1])
+	}
+	if id, ok := graph.NodeAt(-1, 0); ok {
+		t.Errorf("NodeAt(-1, 0) = %d, want none", id)
+	}
+}
+
+type namedBoard struct {
+	name  string
+	board [][]int
+}
+
+// Boards of growing size for the graph benchmarks
+func graphBenchBoards(b *testing.B) []namedBoard {
+	boards := []namedBoard{{"testMedium", loadTestBoard(b, "testMedium")}}
+	for _, size := range []int{12, 30} {
+		generated, _ := generateBoard(size, size, size*size*85/100, rand.New(rand.NewSource(1)))
+		boards = append(boards, namedBoard{fmt.Sprintf("%dx%d", size, size), generated.Board})
+	}
+	return boards
+}
+
+func BenchmarkBoardToGraph(b *testing.B) {
+	for _, nb := range graphBenchBoards(b) {
+		board := nb.board
+		b.Run(nb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := BoardToGraph(board); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGraphTraversal(b *testing.B) {
+	for _, nb := range graphBenchBoards(b) {
+		graph, startID, err := buildGraph(nb.board)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(nb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				DFS(context.Background(), graph, startID, Options{MaxNodes: 20000, DisablePruning: true})
+			}
+		})
+	}
+}
@@ -1,88 +1,126 @@
 package algorithm
 
 import (
+	"context"
 	"fmt"
-	"sort"
 )
 
+// Solver using the Greedy algorithm
+type greedySolver struct{}
+
+func init() {
+	Register(greedySolver{})
+}
+
+func (greedySolver) Name() string {
+	return "greed"
+}
+
+func (greedySolver) Solve(ctx context.Context, board [][]int, opts Options) Result {
+	return Greedy(ctx, board, opts)
+}
+
 // Greedy algorithm to solve dot-connect game
-func Greedy(board [][]int) ([][2]int, bool) {
-	fmt.Println("[Greedy] starting algorithm")
+func Greedy(ctx context.Context, board [][]int, opts Options) Result {
+	fmt.Fprintln(logOutput, "[Greedy] starting algorithm")
 
-	startPoint, usableDotCount, solvable := PreCheckBoard(board)
-	if !solvable {
-		return nil, false
+	startPoint, usableDotCount, err := PreCheckBoard(board)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
 	}
 
-	visited := make(map[[2]int]bool)
+	prefix, err := startingPath(board, startPoint, opts.Prefix)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	// Continue the search from the last dot of the prefix
+	b := NewBitBoard(board)
+	s := newSearch(ctx, opts)
+	s.usePruner(b.Rows * b.Cols)
+	s.useTranspositions()
+	for _, cell := range prefix[:len(prefix)-1] {
+		b.Visit(b.Index(cell[0], cell[1]))
+		s.toggleVisited(b.Index(cell[0], cell[1]))
+	}
+	head := prefix[len(prefix)-1]
+
+	// The path has room for every dot, so the recursion never reallocates it
+	currPath := make([][2]int, len(prefix)-1, usableDotCount)
+	copy(currPath, prefix)
 
-	path, found := GreedyRecursive(board, startPoint[0], startPoint[1], visited, nil, usableDotCount)
+	path, found := GreedyRecursive(s, b, b.Index(head[0], head[1]), currPath, usableDotCount)
 
-	return path, found
+	return s.result(path, found)
 }
 
-func GreedyRecursive(board [][]int, r, c int, visited map[[2]int]bool, currPath [][2]int, usableDotCount int) ([][2]int, bool) {
-	visited[[2]int{r, c}] = true
-	currPath = append(currPath, [2]int{r, c})
+func GreedyRecursive(s *search, b *BitBoard, cell int, currPath [][2]int, usableDotCount int) ([][2]int, bool) {
+	if !s.expand(len(currPath) + 1) {
+		return nil, false
+	}
+
+	b.Visit(cell)
+	s.toggleVisited(cell)
+	currPath = append(currPath, b.Cell(cell))
+	s.tracePush(b.Cell(cell), len(currPath))
 
 	// Check if all usable dots are visited
 	if len(currPath) == usableDotCount {
 		return currPath, true
 	}
 
-	// Possible directions (up, down, left, right)
-	directions := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	// Stop if the same state was already explored, or if the unvisited dots can no longer be completed
+	if s.knownDead(cell) {
+		b.Unvisit(cell)
+		s.toggleVisited(cell)
+		s.traceBacktrack(b.Cell(cell), len(currPath)-1)
+		return nil, false
+	}
+	if s.pruner != nil && s.pruner.gridDeadEnd(b, cell, usableDotCount-len(currPath)) {
+		s.stats.Pruned++
+		s.markDead(cell)
+		b.Unvisit(cell)
+		s.toggleVisited(cell)
+		s.traceBacktrack(b.Cell(cell), len(currPath)-1)
+		return nil, false
+	}
 
 	type neighbor struct {
-		position    [2]int
+		cell        int
 		connections int
 	}
-	neighbors := []neighbor{}
-
-	for _, dir := range directions {
-		newR := r + dir[0]
-		newC := c + dir[1]
-
-		if isValidMove(board, newR, newC, visited) {
-			connections := countConnections(board, newR, newC, visited)
-			neighbors = append(neighbors, neighbor{
-				position:    [2]int{newR, newC},
-				connections: connections,
-			})
+	var neighbors [4]neighbor
+	count := 0
+
+	// Possible directions (up, down, left, right)
+	for _, next := range b.Neighbors(cell) {
+		if next != -1 && b.IsFree(next) {
+			neighbors[count] = neighbor{cell: next, connections: b.Connections(next)}
+			count++
 		}
 	}
 
-	// Sort neighbors by the number of active connections
-	sort.Slice(neighbors, func(i, j int) bool {
-		return neighbors[i].connections > neighbors[j].connections
-	})
+	// Sort neighbors by the number of active connections, ties keep the direction order
+	for i := 1; i < count; i++ {
+		for j := i; j > 0 && neighbors[j].connections > neighbors[j-1].connections; j-- {
+			neighbors[j], neighbors[j-1] = neighbors[j-1], neighbors[j]
+		}
+	}
 
 	// Try each neighbor in order of active connections
-	for _, nbr := range neighbors {
-		path, found := GreedyRecursive(board, nbr.position[0], nbr.position[1], visited, currPath, usableDotCount)
+	for _, nbr := range neighbors[:count] {
+		path, found := GreedyRecursive(s, b, nbr.cell, currPath, usableDotCount)
 		if found {
 			return path, true
 		}
 	}
 
-	visited[[2]int{r, c}] = false
+	// Backtrack
+	s.stats.Backtracks++
+	s.markDead(cell)
+	b.Unvisit(cell)
+	s.toggleVisited(cell)
+	s.traceBacktrack(b.Cell(cell), len(currPath)-1)
 
 	return nil, false
 }
-
-// Count the active connection
-func countConnections(board [][]int, r, c int, visited map[[2]int]bool) int {
-	connections := 0
-	directions := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
-
-	for _, dir := range directions {
-		newR := r + dir[0]
-		newC := c + dir[1]
-
-		if isValidMove(board, newR, newC, visited) {
-			connections++
-		}
-	}
-
-	return connections
-}
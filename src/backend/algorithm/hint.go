@@ -0,0 +1,88 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Hint for a player who has drawn part of the path
+type Hint struct {
+	Completable bool    `json:"completable"`           // The drawn path can still become a solution
+	Next        *[2]int `json:"next,omitempty"`        // Next dot of a solution, after backtracking if needed
+	BacktrackTo *[2]int `json:"backtrackTo,omitempty"` // Dot to backtrack to when the drawn path is not completable
+	TimedOut    bool    `json:"timedOut"`
+	Reason      string  `json:"reason,omitempty"`
+}
+
+// Get a hint for the player's partial path.
+// If the path can still be completed the hint holds the next dot of some completion,
+// otherwise it holds the last dot of the longest completable beginning of the path and the dot that follows it.
+// Returns an error if path is not a valid beginning of a path.
+func GetHint(ctx context.Context, board [][]int, path [][2]int, opts Options) (Hint, error) {
+	fmt.Fprintln(logOutput, "[Hint] starting algorithm")
+
+	graph, startID, err := buildGraph(board)
+	if err != nil {
+		return Hint{Reason: err.Error()}, nil
+	}
+	if _, err := graph.startingPath(startID, path); err != nil {
+		return Hint{}, err
+	}
+	if err := checkGraph(graph, startID); err != nil {
+		return Hint{Reason: err.Error()}, nil
+	}
+	if len(path) == 0 {
+		path = idsToPath(graph, []int{startID})
+	}
+
+	// Every attempt shares the time budget
+	if opts.MaxTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.MaxTime)
+		defer cancel()
+		opts.MaxTime = 0
+	}
+
+	complete := func(length int) Result {
+		attempt := opts
+		attempt.Prefix = path[:length]
+		return DFS(ctx, graph, startID, attempt)
+	}
+
+	result := complete(len(path))
+	switch result.Status {
+	case StatusTimedOut:
+		return Hint{TimedOut: true}, nil
+	case StatusFound:
+		hint := Hint{Completable: true}
+		if len(result.Path) > len(path) {
+			hint.Next = &result.Path[len(path)]
+		}
+		return hint, nil
+	}
+
+	// Find the longest completable beginning of the path, a beginning of a completable path is completable too
+	low, high := 0, len(path)-1
+	var best Result
+	for low < high {
+		length := (low + high + 1) / 2
+		result := complete(length)
+		switch result.Status {
+		case StatusTimedOut:
+			return Hint{TimedOut: true}, nil
+		case StatusFound:
+			low, best = length, result
+		default:
+			high = length - 1
+		}
+	}
+
+	if low == 0 {
+		return Hint{Reason: "board has no solution"}, nil
+	}
+
+	return Hint{
+		BacktrackTo: &path[low-1],
+		Next:        &best.Path[low],
+	}, nil
+}
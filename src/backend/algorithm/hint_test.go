@@ -0,0 +1,63 @@
+package algorithm
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetHint(t *testing.T) {
+	open := [][]int{
+		{2, 0, 0},
+		{0, 0, 0},
+		{0, 0, 0},
+	}
+
+	tests := []struct {
+		name        string
+		board       [][]int
+		path        [][2]int
+		completable bool
+		backtrackTo *[2]int
+		next        *[2]int
+		reason      bool
+	}{
+		{"completable", open, [][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 2}}, true, nil, nil, false},
+		{"dead end", open, [][2]int{{0, 0}, {1, 0}, {1, 1}, {1, 2}}, false, &[2]int{1, 1}, &[2]int{0, 1}, false},
+		{"no solution", [][]int{{1, 1, 0, 1}, {0, 0, 0, 0}, {0, 0, 2, 0}}, nil, false, nil, nil, true},
+		{"rejected board", [][]int{{0, 2, 0}, {0, 0, 0}, {0, 0, 0}}, nil, false, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hint, err := GetHint(context.Background(), tt.board, tt.path, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hint.Completable != tt.completable || hint.TimedOut || (hint.Reason != "") != tt.reason {
+				t.Fatalf("got %+v", hint)
+			}
+			if tt.backtrackTo != nil && (hint.BacktrackTo == nil || *hint.BacktrackTo != *tt.backtrackTo) {
+				t.Errorf("backtrack to %v, want %v", hint.BacktrackTo, *tt.backtrackTo)
+			}
+			if tt.next != nil && (hint.Next == nil || *hint.Next != *tt.next) {
+				t.Errorf("next %v, want %v", hint.Next, *tt.next)
+			}
+
+			// The next dot of a completable path continues a solution
+			if tt.completable {
+				if hint.Next == nil {
+					t.Fatal("no next dot")
+				}
+				prefix := append(append([][2]int(nil), tt.path...), *hint.Next)
+				if result := (dfsSolver{}).Solve(context.Background(), tt.board, Options{Prefix: prefix}); !result.Found() {
+					t.Errorf("next dot %v cannot be completed: %s", *hint.Next, result.Status)
+				}
+			}
+		})
+	}
+
+	// A path that is not the beginning of a path is an error, not a hint
+	if _, err := GetHint(context.Background(), open, [][2]int{{0, 0}, {1, 1}}, Options{}); err == nil {
+		t.Errorf("diagonal step accepted")
+	}
+}
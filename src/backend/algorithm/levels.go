@@ -0,0 +1,106 @@
+package algorithm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Level describes the boards generated for a level
+type Level struct {
+	Name            string  `json:"name"`
+	Rows            int     `json:"rows"`
+	Columns         int     `json:"columns"`
+	ObstacleDensity float64 `json:"obstacleDensity"` // Share of the cells that are blocked, from 0 to 1
+	Generator       string  `json:"generator"`       // Name of the generator strategy, see generators
+}
+
+// Levels used when no level file is loaded
+var defaultLevels = []Level{
+	{Name: "beginner", Rows: 5, Columns: 5, ObstacleDensity: 0.15, Generator: "backbite"},
+	{Name: "easy", Rows: 8, Columns: 6, ObstacleDensity: 0.15, Generator: "backbite"},
+	{Name: "medium", Rows: 10, Columns: 6, ObstacleDensity: 0.15, Generator: "backbite"},
+	{Name: "hard", Rows: 12, Columns: 8, ObstacleDensity: 0.15, Generator: "backbite"},
+}
+
+// Current levels, in the order they are listed
+var levels = defaultLevels
+
+// List the levels
+func Levels() []Level {
+	return append([]Level(nil), levels...)
+}
+
+// Get a level by name
+func GetLevel(name string) (Level, error) {
+	for _, level := range levels {
+		if level.Name == name {
+			return level, nil
+		}
+	}
+	return Level{}, fmt.Errorf("invalid level: %s", name)
+}
+
+// Replace the levels, after checking every one of them.
+// Not safe to call while boards are being generated, meant to be done at startup.
+func SetLevels(newLevels []Level) error {
+	if len(newLevels) == 0 {
+		return fmt.Errorf("no levels defined")
+	}
+
+	seen := make(map[string]bool)
+	for _, level := range newLevels {
+		if err := level.validate(); err != nil {
+			return err
+		}
+		if seen[level.Name] {
+			return fmt.Errorf("level %s is defined twice", level.Name)
+		}
+		seen[level.Name] = true
+	}
+
+	levels = append([]Level(nil), newLevels...)
+	return nil
+}
+
+// Load the levels from a JSON file holding a list of levels
+func LoadLevels(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var fileLevels []Level
+	if err := json.Unmarshal(data, &fileLevels); err != nil {
+		return fmt.Errorf("invalid level file %s: %w", filename, err)
+	}
+
+	return SetLevels(fileLevels)
+}
+
+// Check that boards can be generated for the level
+func (level Level) validate() error {
+	if level.Name == "" {
+		return fmt.Errorf("level without a name")
+	}
+	if level.Rows < 1 || level.Columns < 1 || level.Rows*level.Columns < 2 {
+		return fmt.Errorf("level %s: board must have at least 2 cells", level.Name)
+	}
+	if level.ObstacleDensity < 0 || level.ObstacleDensity >= 1 {
+		return fmt.Errorf("level %s: obstacle density must be in [0, 1)", level.Name)
+	}
+	if level.freeCells() < 2 {
+		return fmt.Errorf("level %s: too many obstacles", level.Name)
+	}
+	if _, ok := generators[level.Generator]; !ok {
+		return fmt.Errorf("level %s: unknown generator %q", level.Name, level.Generator)
+	}
+	return nil
+}
+
+// Number of cells left open on the boards of the level
+func (level Level) freeCells() int {
+	numCells := level.Rows * level.Columns
+	// The epsilon keeps densities such as 0.15 from rounding one obstacle down
+	return numCells - int(float64(numCells)*level.ObstacleDensity+1e-9)
+}
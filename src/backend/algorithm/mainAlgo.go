@@ -1,54 +1,236 @@
 package algorithm
 
-// PreCheckBoard checks if the board is solvable or not.
-func PreCheckBoard(board [][]int) ([2]int, int, bool) {
-	rows := len(board)
-	cols := len(board[0])
-
-	var startPoint [2]int
-	usableDotCount := 0
-	countEndPoint := 0
-
-	for r := 0; r < rows; r++ {
-		for c := 0; c < cols; c++ {
-			if board[r][c] == 2 {
-				startPoint = [2]int{r, c}
-				usableDotCount++
-			}
-			if board[r][c] == 0 {
-				usableDotCount++
-				connection := 0
-				if r+1 < rows && (board[r+1][c] == 0 || board[r+1][c] == 2) {
-					connection++
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Amount of search tree branches prepared per worker, more branches balance the load better
+const branchesPerWorker = 8
+
+// Solver using the parallel DFS
+type mainSolver struct{}
+
+func init() {
+	Register(mainSolver{})
+}
+
+func (mainSolver) Name() string {
+	return "main"
+}
+
+func (mainSolver) Solve(ctx context.Context, board [][]int, opts Options) Result {
+	graph, startID, err := BoardToGraph(board)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	return MainAlgo(ctx, graph, startID, opts)
+}
+
+// MainAlgo algorithm, a parallel DFS.
+// The top of the search tree is expanded breadth first into branches (path prefixes),
+// the branches are then searched with DFS by up to GOMAXPROCS goroutines.
+// Every goroutine stops as soon as one of them finds a solution, the goroutines share one budget.
+// Each goroutine traces the branch it takes as pushes, and as backtracks once it is done with it.
+// With a single goroutine there is nothing to split, the search is a plain DFS.
+func MainAlgo(ctx context.Context, graph *Graph, startID int, opts Options) Result {
+	fmt.Fprintln(logOutput, "[MainAlgo] starting algorithm")
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers == 1 {
+		return DFS(ctx, graph, startID, opts)
+	}
+
+	s := newSearch(ctx, opts)
+	s.trace = serializeTrace(s.trace)
+	s.usePruner(len(graph.Nodes))
+
+	prefix, err := graph.startingPath(startID, opts.Prefix)
+	if err != nil {
+		return Result{Status: StatusNotFound, Reason: err.Error()}
+	}
+
+	branches, solution := splitSearchTree(s, graph, prefix, workers*branchesPerWorker)
+	if solution != nil {
+		for i, id := range solution {
+			s.tracePush(nodeCell(graph, id), i+1)
+		}
+		return s.result(idsToPath(graph, solution), true)
+	}
+	if len(branches) < workers {
+		workers = len(branches)
+	}
+
+	tasks := make(chan []int)
+	var found atomic.Bool
+	var once sync.Once
+	var result []int
+	var wg sync.WaitGroup
+	workerSearches := make([]*search, workers)
+
+	for i := range workerSearches {
+		workerSearches[i] = s.fork()
+		workerSearches[i].worker = i
+		wg.Add(1)
+		go func(s *search) {
+			defer wg.Done()
+			s.usePruner(len(graph.Nodes))
+
+			visited := make([]bool, len(graph.Nodes))
+			for branch := range tasks {
+				if found.Load() {
+					continue
 				}
-				if r-1 >= 0 && (board[r-1][c] == 0 || board[r-1][c] == 2) {
-					connection++
+
+				for i, id := range branch {
+					visited[id] = true
+					s.tracePush(nodeCell(graph, id), i+1)
 				}
-				if c+1 < cols && (board[r][c+1] == 0 || board[r][c+1] == 2) {
-					connection++
+				path := append([]int(nil), branch...)
+				if MainAlgoRecursive(s, graph, branch[len(branch)-1], visited, &path, &found) {
+					once.Do(func() {
+						result = path
+					})
+					found.Store(true)
+					continue
 				}
-				if c-1 >= 0 && (board[r][c-1] == 0 || board[r][c-1] == 2) {
-					connection++
+				for i := len(branch) - 1; i >= 0; i-- {
+					visited[branch[i]] = false
+					s.traceBacktrack(nodeCell(graph, branch[i]), i)
 				}
-				if connection == 0 {
-					return [2]int{0, 0}, 0, false
-				} else if connection == 1 {
-					countEndPoint++
-					if countEndPoint > 1 {
-						return [2]int{0, 0}, 0, false
+			}
+		}(workerSearches[i])
+	}
+
+	for _, branch := range branches {
+		if found.Load() || s.exceeded.Load() {
+			break
+		}
+		tasks <- branch
+	}
+	close(tasks)
+	wg.Wait()
+
+	for _, worker := range workerSearches {
+		s.stats.merge(worker.stats)
+	}
+
+	if result == nil {
+		return s.result(nil, false)
+	}
+
+	return s.result(idsToPath(graph, result), true)
+}
+
+// Continue the DFS from the last node of path, the last node is already marked as visited.
+// Returns early when another goroutine has set found or the budget ran out.
+func MainAlgoRecursive(s *search, graph *Graph, currentID int, visited []bool, path *[]int, found *atomic.Bool) bool {
+	if found.Load() || !s.expand(len(*path)) {
+		return false
+	}
+	if len(*path) == len(graph.Nodes) {
+		return true
+	}
+
+	// Stop if the unvisited nodes can no longer be completed
+	if s.pruner != nil && s.pruner.graphDeadEnd(graph, visited, currentID, len(graph.Nodes)-len(*path)) {
+		s.stats.Pruned++
+		return false
+	}
+
+	for _, neighbor := range graph.Edges(currentID) {
+		if !visited[neighbor] {
+			visited[neighbor] = true
+			*path = append(*path, neighbor)
+			s.tracePush(nodeCell(graph, neighbor), len(*path))
+
+			if MainAlgoRecursive(s, graph, neighbor, visited, path, found) {
+				return true
+			}
+
+			// Backtrack
+			s.stats.Backtracks++
+			visited[neighbor] = false
+			*path = (*path)[:len(*path)-1]
+			s.traceBacktrack(nodeCell(graph, neighbor), len(*path))
+		}
+	}
+
+	return false
+}
+
+// Expand the search tree below prefix breadth first until there are at least target branches.
+// Every expanded branch counts against the budget of s and dead branches are pruned like in the DFS.
+// Returns the branches as node ID prefixes, or a full solution if one is met on the way.
+func splitSearchTree(s *search, graph *Graph, prefix []int, target int) ([][]int, []int) {
+	branches := [][]int{prefix}
+	visited := make([]bool, len(graph.Nodes))
+
+	for len(branches) > 0 && len(branches) < target {
+		var next [][]int
+		for _, branch := range branches {
+			if len(branch) == len(graph.Nodes) {
+				return nil, branch
+			}
+			if !s.expand(len(branch)) {
+				return nil, nil
+			}
+
+			for _, id := range branch {
+				visited[id] = true
+			}
+			last := branch[len(branch)-1]
+			if s.pruner != nil && s.pruner.graphDeadEnd(graph, visited, last, len(graph.Nodes)-len(branch)) {
+				s.stats.Pruned++
+			} else {
+				for _, neighbor := range graph.Edges(last) {
+					if !visited[neighbor] {
+						child := make([]int, len(branch), len(branch)+1)
+						copy(child, branch)
+						next = append(next, append(child, neighbor))
 					}
 				}
 			}
+			for _, id := range branch {
+				visited[id] = false
+			}
+		}
+		branches = next
+	}
+
+	for _, branch := range branches {
+		if len(branch) == len(graph.Nodes) {
+			return nil, branch
 		}
 	}
 
-	return startPoint, usableDotCount, true
+	return branches, nil
 }
 
-// MainAlgo algorithm
-// Not Finished
-// Ideas:
-// - bruteforce/dfs added concurentcy
-// - Group the dots into blocks (n x m)
-// - Have a template of how to solve (n x m)
-// - Avoid making more endpoints (dots with 1 connection)
+// Check if a path of node IDs contains id
+func containsID(path []int, id int) bool {
+	for _, v := range path {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Convert a path of node IDs into board coordinates
+func idsToPath(graph *Graph, ids []int) [][2]int {
+	path := make([][2]int, len(ids))
+	for i, id := range ids {
+		path[i] = nodeCell(graph, id)
+	}
+	return path
+}
+
+// Board coordinates of a node
+func nodeCell(graph *Graph, id int) [2]int {
+	return [2]int{graph.Nodes[id].X, graph.Nodes[id].Y}
+}
@@ -0,0 +1,45 @@
+package algorithm
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestMainAlgoSingleWorkerIsDFS(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	board := loadTestBoard(t, "testMedium")
+	graph, startID, err := BoardToGraph(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{MaxNodes: testNodeBudget}
+	main := MainAlgo(context.Background(), graph, startID, opts)
+	dfs := DFS(context.Background(), graph, startID, opts)
+	main.Stats.TimeNs, dfs.Stats.TimeNs = 0, 0
+	if main.Status != dfs.Status || main.Stats != dfs.Stats {
+		t.Errorf("main got %s %+v, DFS got %s %+v", main.Status, main.Stats, dfs.Status, dfs.Stats)
+	}
+}
+
+func TestMainAlgoSplitCountsAgainstBudget(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	board := make([][]int, 6)
+	for r := range board {
+		board[r] = make([]int, 6)
+	}
+	board[0][0] = 2
+	graph, startID, err := BoardToGraph(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Splitting the open board into 32 branches alone takes more nodes than the budget
+	result := MainAlgo(context.Background(), graph, startID, Options{MaxNodes: 5})
+	if result.Status != StatusTimedOut || result.Stats.NodesExpanded > 5 {
+		t.Errorf("got %s after %d nodes, want timed out within 5", result.Status, result.Stats.NodesExpanded)
+	}
+}
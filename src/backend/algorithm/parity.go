@@ -0,0 +1,23 @@
+package algorithm
+
+import "fmt"
+
+// Colour of a cell when the board is painted like a checkerboard
+func cellColor(r, c int) int {
+	return (r + c) % 2
+}
+
+// Check the checkerboard parity of the usable dots.
+// Every move of a path changes colour, so a path over all the dots that starts on
+// the starting colour needs as many dots of that colour as of the other one, or one more.
+// colorCount holds the amount of usable dots of each colour, startColor the colour of the starting dot.
+func checkParity(colorCount [2]int, startColor int) error {
+	same := colorCount[startColor]
+	other := colorCount[1-startColor]
+
+	if same != other && same != other+1 {
+		return fmt.Errorf("parity: %d dots share the colour of the starting dot and %d do not, a path needs equal amounts or one more on the starting colour", same, other)
+	}
+
+	return nil
+}
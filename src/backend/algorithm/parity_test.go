@@ -0,0 +1,42 @@
+package algorithm
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParityRejectsBoard(t *testing.T) {
+	// 5 dots share the colour of the corners and 4 do not, a path from an edge cannot cover them
+	board := [][]int{
+		{0, 2, 0},
+		{0, 0, 0},
+		{0, 0, 0},
+	}
+
+	if _, _, err := PreCheckBoard(board); err == nil || !strings.HasPrefix(err.Error(), "parity:") {
+		t.Errorf("PreCheckBoard: got %v, want a parity error", err)
+	}
+	if _, _, err := BoardToGraph(board); err == nil || !strings.HasPrefix(err.Error(), "parity:") {
+		t.Errorf("BoardToGraph: got %v, want a parity error", err)
+	}
+
+	for _, name := range SolverNames() {
+		solver, _ := GetSolver(name)
+		result := solver.Solve(context.Background(), board, Options{})
+
+		// The DP skips the pre-checks on purpose, it confirms the board has no solution
+		if name == "dp" {
+			if result.Status != StatusNotFound {
+				t.Errorf("dp: got %s, want not found", result.Status)
+			}
+			continue
+		}
+		if result.Status != StatusNotFound || !strings.HasPrefix(result.Reason, "parity:") {
+			t.Errorf("%s: got %s (%q), want not found for parity", name, result.Status, result.Reason)
+		}
+		if result.Stats.NodesExpanded != 0 {
+			t.Errorf("%s: expanded %d nodes on a rejected board", name, result.Stats.NodesExpanded)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package algorithm
+
+import "fmt"
+
+// Check that prefix is the beginning of a path: it starts at the starting dot, stays on usable dots,
+// moves one orthogonal step at a time and never repeats a dot. An empty prefix is valid.
+func checkPrefix(board [][]int, prefix [][2]int) error {
+	if _, err := verifySteps(board, prefix); err != nil {
+		return fmt.Errorf("invalid prefix: %w", err)
+	}
+
+	return nil
+}
+
+// Path the search of a board starts with, the prefix or only the starting point when it is empty
+func startingPath(board [][]int, startPoint [2]int, prefix [][2]int) ([][2]int, error) {
+	if len(prefix) == 0 {
+		return [][2]int{startPoint}, nil
+	}
+	if err := checkPrefix(board, prefix); err != nil {
+		return nil, err
+	}
+
+	return append([][2]int(nil), prefix...), nil
+}
+
+// Path of node IDs the search of a graph starts with, the prefix or only the starting node when it is empty
+func (g *Graph) startingPath(startID int, prefix [][2]int) ([]int, error) {
+	if len(prefix) == 0 {
+		return []int{startID}, nil
+	}
+
+	ids := make([]int, len(prefix))
+	seen := make(map[int]bool, len(prefix))
+	for i, cell := range prefix {
+		id, ok := g.NodeAt(cell[0], cell[1])
+		if !ok {
+			return nil, fmt.Errorf("invalid prefix: dot %d at (%d, %d) is not usable", i, cell[0], cell[1])
+		}
+		if i == 0 && id != startID {
+			return nil, fmt.Errorf("invalid prefix: does not begin at the starting dot")
+		}
+		if i > 0 && !containsID(g.Edges(ids[i-1]), id) {
+			return nil, fmt.Errorf("invalid prefix: dot %d at (%d, %d) is not next to the previous dot", i, cell[0], cell[1])
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("invalid prefix: dot %d at (%d, %d) is visited twice", i, cell[0], cell[1])
+		}
+		seen[id] = true
+		ids[i] = id
+	}
+
+	return ids, nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
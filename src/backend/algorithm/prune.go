@@ -0,0 +1,127 @@
+package algorithm
+
+import "math/bits"
+
+// pruner detects branches of the search that can no longer become a solution.
+// After every move the unvisited dots are flood filled from the head of the path:
+//   - every unvisited dot must still be reachable from the head
+//   - at most one unvisited dot may have a single free connection (it must be the end of the path)
+//   - a dot whose only free connection is the head must be the last dot left
+//
+// The buffers are reused between checks, one pruner must not be shared between goroutines.
+type pruner struct {
+	stack []int
+	seen  []uint32 // seen[i] == gen marks dot i as reached in the current check
+	gen   uint32
+}
+
+// Create a pruner for a search over size dots (node IDs or row*cols+col)
+func newPruner(size int) *pruner {
+	return &pruner{
+		stack: make([]int, 0, size),
+		seen:  make([]uint32, size),
+	}
+}
+
+// Start a new flood fill
+func (p *pruner) reset() {
+	p.gen++
+	if p.gen == 0 {
+		clear(p.seen)
+		p.gen = 1
+	}
+	p.stack = p.stack[:0]
+}
+
+// Check a graph search whose path ends in head with remaining unvisited nodes
+func (p *pruner) graphDeadEnd(graph *Graph, visited []bool, head, remaining int) bool {
+	p.reset()
+
+	for _, neighbor := range graph.Edges(head) {
+		if !visited[neighbor] && p.seen[neighbor] != p.gen {
+			p.seen[neighbor] = p.gen
+			p.stack = append(p.stack, neighbor)
+		}
+	}
+
+	reached := 0
+	endPoints := 0
+	for len(p.stack) > 0 {
+		id := p.stack[len(p.stack)-1]
+		p.stack = p.stack[:len(p.stack)-1]
+		reached++
+
+		free, touchesHead := 0, false
+		for _, neighbor := range graph.Edges(id) {
+			if neighbor == head {
+				touchesHead = true
+			} else if !visited[neighbor] {
+				free++
+				if p.seen[neighbor] != p.gen {
+					p.seen[neighbor] = p.gen
+					p.stack = append(p.stack, neighbor)
+				}
+			}
+		}
+
+		if isDeadEnd(free, touchesHead, remaining, &endPoints) {
+			return true
+		}
+	}
+
+	return reached < remaining
+}
+
+// Check a grid search whose path ends in the cell head with remaining unvisited dots
+func (p *pruner) gridDeadEnd(b *BitBoard, head, remaining int) bool {
+	p.reset()
+	p.pushFree(b, head)
+
+	reached := 0
+	endPoints := 0
+	for len(p.stack) > 0 {
+		id := p.stack[len(p.stack)-1]
+		p.stack = p.stack[:len(p.stack)-1]
+		reached++
+
+		// The head is visited, so it is not counted as a free connection
+		p.pushFree(b, id)
+		if isDeadEnd(b.Connections(id), b.Adjacent(id, head), remaining, &endPoints) {
+			return true
+		}
+	}
+
+	return reached < remaining
+}
+
+// Push the free neighbours of the cell i that were not reached yet
+func (p *pruner) pushFree(b *BitBoard, i int) {
+	m := &b.masks[i]
+	for k := 0; k < m.count; k++ {
+		for word := b.free[m.words[k]] & m.masks[k]; word != 0; word &= word - 1 {
+			j := m.words[k]*64 + bits.TrailingZeros64(word)
+			if p.seen[j] != p.gen {
+				p.seen[j] = p.gen
+				p.stack = append(p.stack, j)
+			}
+		}
+	}
+}
+
+// Check a reached dot with free unvisited neighbours, counting the forced end points
+func isDeadEnd(free int, touchesHead bool, remaining int, endPoints *int) bool {
+	// Only reachable from the head, the path would end here with dots left over
+	if free == 0 && remaining > 1 {
+		return true
+	}
+
+	connections := free
+	if touchesHead {
+		connections++
+	}
+	if connections <= 1 {
+		*endPoints++
+	}
+
+	return *endPoints > 1
+}
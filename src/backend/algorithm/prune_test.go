@@ -0,0 +1,52 @@
+package algorithm
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Load a board from the test directory at the root of the repository
+func loadTestBoard(tb testing.TB, name string) [][]int {
+	tb.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "test", name+".json"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	var file struct {
+		Board [][]int `json:"board"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		tb.Fatal(err)
+	}
+
+	return file.Board
+}
+
+func BenchmarkPruning(b *testing.B) {
+	for _, name := range []string{"testEasy", "testMedium"} {
+		board := loadTestBoard(b, name)
+		graph, startID, err := BoardToGraph(board)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, bench := range []struct {
+			name string
+			opts Options
+		}{
+			{"pruned", Options{}},
+			{"unpruned", Options{DisablePruning: true}},
+		} {
+			b.Run(name+"/"+bench.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					DFS(context.Background(), graph, startID, bench.opts)
+				}
+			})
+		}
+	}
+}
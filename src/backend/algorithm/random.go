@@ -1,65 +1,188 @@
 package algorithm
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"time"
 )
 
-var boardSizes = map[string][2]int{
-	"beginner": {5, 5},
-	"easy":     {8, 6},
-	"medium":   {10, 6},
-	"hard":     {12, 8},
+// Generator strategies a level can use, each builds a board with the given number of open cells
+var generators = map[string]func(rows, cols, freeCells int, rng *rand.Rand) (*GeneratedBoard, error){
+	"backbite": generateBoard,
+	"scatter":  scatterBoard,
 }
 
+// Boards tried by the scatter generator before giving up
+const maxScatterAttempts = 100
+
+// Most backbite moves done to shuffle the path of a generated board
+const maxBackbiteMoves = 200000
+
+// GeneratedBoard is a random board together with the path it was built from
+type GeneratedBoard struct {
+	Board      [][]int    `json:"board"`
+	Solution   [][2]int   `json:"solution"`
+	Seed       int64      `json:"seed"`       // Generating the same level with this seed gives the same board
+	Difficulty Difficulty `json:"difficulty"` // Only set once the board is rated
+}
+
+// Generate a random board of the level that is solvable by construction:
+// a random path is drawn on the grid first, then every cell the path does not use is blocked.
 func GenerateRandomBoard(level string) ([][]int, error) {
-	size, ok := boardSizes[level]
-	if !ok {
-		return nil, fmt.Errorf("invalid level: %s", level)
+	generated, err := GenerateSeededBoard(level, NewSeed())
+	if err != nil {
+		return nil, err
+	}
+
+	return generated.Board, nil
+}
+
+// Largest seed in absolute value, seeds stay below 2^53 so they survive a round trip through JavaScript numbers
+const MaxSeed = 1<<53 - 1
+
+// Pick a fresh seed from the clock
+func NewSeed() int64 {
+	return time.Now().UnixNano() & MaxSeed
+}
+
+// Generate the solvable board of the level for seed, the same seed always gives the same board.
+// The board is not rated, Rate does it on demand.
+func GenerateSeededBoard(level string, seed int64) (*GeneratedBoard, error) {
+	lvl, err := GetLevel(level)
+	if err != nil {
+		return nil, err
 	}
 
-	rows, cols := size[0], size[1]
+	rng := rand.New(rand.NewSource(seed))
+
+	generated, err := generators[lvl.Generator](lvl.Rows, lvl.Columns, lvl.freeCells(), rng)
+	if err != nil {
+		return nil, err
+	}
+	generated.Seed = seed
+
+	return generated, nil
+}
+
+// Rate the difficulty of the generated board, with its known solution in case the rating runs out of budget
+func (g *GeneratedBoard) Rate(ctx context.Context) {
+	g.Difficulty = RateDifficulty(ctx, g.Board, g.Solution)
+}
+
+// Generate a rows x cols board around a random path
+func generateBoard(rows, cols, freeCells int, rng *rand.Rand) (*GeneratedBoard, error) {
+	path := randomPath(rows, cols, freeCells, rng)
+
 	board := make([][]int, rows)
 	for i := range board {
 		board[i] = make([]int, cols)
+		for j := range board[i] {
+			board[i][j] = 1
+		}
 	}
-
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-
-	for {
-		placeStartingDot(board, rows, cols, rng)
-		fillBoard(board, rows, cols, rng)
-		return board, nil
-		// If the random board must be solveable
-		// if isSolvable(board) {
-		// 	return board, nil
-		// }
-		// for i := range board {
-		// 	for j := range board[i] {
-		// 		board[i][j] = 0
-		// 	}
-		// }
+	for _, cell := range path {
+		board[cell[0]][cell[1]] = 0
 	}
+	board[path[0][0]][path[0][1]] = 2
+
+	return &GeneratedBoard{Board: board, Solution: path}, nil
 }
 
-func placeStartingDot(board [][]int, rows, cols int, rng *rand.Rand) {
-	r := rng.Intn(rows)
-	c := rng.Intn(cols)
-	board[r][c] = 2
+// Generate a rows x cols board by scattering the obstacles and the starting dot at random,
+// retrying until DFS finds a solution for it
+func scatterBoard(rows, cols, freeCells int, rng *rand.Rand) (*GeneratedBoard, error) {
+	for attempt := 0; attempt < maxScatterAttempts; attempt++ {
+		board := make([][]int, rows)
+		for i := range board {
+			board[i] = make([]int, cols)
+		}
+
+		// Shuffle the cells, the first one holds the starting dot and the ones past freeCells are blocked
+		cells := rng.Perm(rows * cols)
+		board[cells[0]/cols][cells[0]%cols] = 2
+		for _, cell := range cells[freeCells:] {
+			board[cell/cols][cell%cols] = 1
+		}
+
+		result := dfsSolver{}.Solve(context.Background(), board, ratingOptions)
+		if result.Found() {
+			return &GeneratedBoard{Board: board, Solution: result.Path}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no solvable board found after %d attempts", maxScatterAttempts)
 }
 
-func fillBoard(board [][]int, rows, cols int, rng *rand.Rand) {
-	numCells := rows * cols
-	numOnes := numCells * 15 / 100
+// Random self-avoiding path of length cells on a rows x cols grid.
+// Starts from a snake through the rows and shuffles it with backbite moves:
+// the head of the path steps to a random neighbour, if the neighbour is free the tail
+// follows (the path slithers), if it is on the path the loop that forms is reversed.
+func randomPath(rows, cols, length int, rng *rand.Rand) [][2]int {
+	path := make([][2]int, 0, rows*cols)
+	for r := 0; r < rows && len(path) < length; r++ {
+		for i := 0; i < cols && len(path) < length; i++ {
+			c := i
+			if r%2 == 1 {
+				c = cols - 1 - i
+			}
+			path = append(path, [2]int{r, c})
+		}
+	}
 
-	for i := 0; i < numOnes; i++ {
-		r := rng.Intn(rows)
-		c := rng.Intn(cols)
-		for board[r][c] != 0 {
-			r = rng.Intn(rows)
-			c = rng.Intn(cols)
+	// position[r][c] is the index of the cell in path, or -1
+	position := make([][]int, rows)
+	for r := range position {
+		position[r] = make([]int, cols)
+		for c := range position[r] {
+			position[r][c] = -1
+		}
+	}
+	reindex := func(from, to int) {
+		for i := from; i < to; i++ {
+			position[path[i][0]][path[i][1]] = i
 		}
-		board[r][c] = 1
 	}
+	reindex(0, len(path))
+
+	reverse := func(from, to int) {
+		for i, j := from, to-1; i < j; i, j = i+1, j-1 {
+			path[i], path[j] = path[j], path[i]
+		}
+		reindex(from, to)
+	}
+
+	directions := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	moves := rows * cols * rows * cols
+	if moves > maxBackbiteMoves {
+		moves = maxBackbiteMoves
+	}
+
+	for move := 0; move < moves && len(path) > 1; move++ {
+		// Work on either end of the path
+		if rng.Intn(2) == 0 {
+			reverse(0, len(path))
+		}
+
+		head := path[len(path)-1]
+		dir := directions[rng.Intn(len(directions))]
+		r, c := head[0]+dir[0], head[1]+dir[1]
+		if r < 0 || r >= rows || c < 0 || c >= cols {
+			continue
+		}
+
+		if i := position[r][c]; i == -1 {
+			// Slither: step onto the free cell and drop the tail
+			tail := path[0]
+			position[tail[0]][tail[1]] = -1
+			copy(path, path[1:])
+			path[len(path)-1] = [2]int{r, c}
+			reindex(0, len(path))
+		} else if i < len(path)-2 {
+			// Backbite: connect the head to the cell and reverse the loop after it
+			reverse(i+1, len(path))
+		}
+	}
+
+	return path
 }
@@ -0,0 +1,180 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGeneratedBoardsAreSolvable(t *testing.T) {
+	// Scatter rarely finds a solvable layout on large boards, so it is only tried on the small levels
+	levels := Levels()
+	levels = append(levels,
+		Level{Name: "beginner", Rows: 5, Columns: 5, ObstacleDensity: 0.15, Generator: "scatter"},
+		Level{Name: "easy", Rows: 8, Columns: 6, ObstacleDensity: 0.15, Generator: "scatter"},
+	)
+
+	for _, level := range levels {
+		generate := generators[level.Generator]
+		for seed := int64(0); seed < 20; seed++ {
+			generated, err := generate(level.Rows, level.Columns, level.freeCells(), rand.New(rand.NewSource(seed)))
+			if err != nil {
+				t.Errorf("%s %s seed %d: %v", level.Name, level.Generator, seed, err)
+				continue
+			}
+
+			if err := VerifyPath(generated.Board, generated.Solution); err != nil {
+				t.Errorf("%s %s seed %d: solution does not solve the board: %v", level.Name, level.Generator, seed, err)
+			}
+			if _, _, err := BoardToGraph(generated.Board); err != nil {
+				t.Errorf("%s %s seed %d: board rejected: %v", level.Name, level.Generator, seed, err)
+			}
+		}
+	}
+}
+
+func TestSeededBoardsAreReproducible(t *testing.T) {
+	for _, lvl := range Levels() {
+		level := lvl.Name
+		first, err := GenerateSeededBoard(level, 42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, _ := GenerateSeededBoard(level, 42)
+		other, _ := GenerateSeededBoard(level, 43)
+
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("%s: seed 42 gave two different boards", level)
+		}
+		if reflect.DeepEqual(first.Board, other.Board) {
+			t.Errorf("%s: seeds 42 and 43 gave the same board", level)
+		}
+	}
+}
+
+func TestGenerateBoardWithDifficulty(t *testing.T) {
+	band, err := TierBand("medium")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := GenerateBoardWithDifficulty(context.Background(), "easy", band, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !band.Contains(generated.Difficulty.Score) || generated.Difficulty.Tier != "medium" {
+		t.Errorf("score %.1f (%s) is outside %v", generated.Difficulty.Score, generated.Difficulty.Tier, band)
+	}
+
+	// The returned seed alone gives the board back
+	again, _ := GenerateSeededBoard("easy", generated.Seed)
+	again.Rate(context.Background())
+	if !reflect.DeepEqual(generated, again) {
+		t.Errorf("seed %d does not reproduce the board", generated.Seed)
+	}
+}
+
+func TestGenerateBoardWithDifficultyStopsRating(t *testing.T) {
+	band, _ := TierBand("expert")
+
+	// The fourth hard board takes longer to rate than the whole time budget
+	start := time.Now()
+	generated, err := GenerateBoardWithDifficulty(context.Background(), "hard", band, 0, 100*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %v with a budget of 100ms", elapsed)
+	}
+	if !errors.Is(err, ErrDifficultyNotReached) {
+		t.Fatalf("got error %v", err)
+	}
+	if generated.Seed >= 3 || generated.Difficulty.Solvable == nil {
+		t.Errorf("returned seed %d rated %+v, not a fully rated candidate", generated.Seed, generated.Difficulty)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GenerateBoardWithDifficulty(ctx, "hard", band, 0, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v with a cancelled context", err)
+	}
+}
+
+func TestGeneratedDifficultyMatchesRating(t *testing.T) {
+	// A generated board is rated like any other board, whatever solution it was built around,
+	// its own solution only stands in when the rating finds none
+	for _, lvl := range Levels() {
+		for seed := int64(1); seed <= 3; seed++ {
+			generated, err := GenerateSeededBoard(lvl.Name, seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			generated.Rate(context.Background())
+
+			rated := RateDifficulty(context.Background(), generated.Board, nil)
+			if rated.Solvable != nil && !reflect.DeepEqual(rated, generated.Difficulty) {
+				t.Errorf("%s seed %d: rated %+v, generated with %+v", lvl.Name, seed, rated, generated.Difficulty)
+			}
+		}
+	}
+}
+
+func TestCappedRatingIsNotUnsolvable(t *testing.T) {
+	// Neither DFS nor Greedy solve this board within the rating budget
+	generated, err := GenerateSeededBoard("hard", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unknown := RateDifficulty(context.Background(), generated.Board, nil)
+	if unknown.Solvable != nil || unknown.Tier != "unknown" || !unknown.EffortCapped {
+		t.Errorf("rated %+v without a solution, want an unknown tier", unknown)
+	}
+
+	generated.Rate(context.Background())
+	d := generated.Difficulty
+	if d.Solvable == nil || !*d.Solvable || d.ForcedMoves == 0 || d.Branching == 0 {
+		t.Errorf("rated %+v with the known solution, want its measures", d)
+	}
+	if d.Score >= 80 {
+		t.Errorf("score %.1f only comes from the budget running out", d.Score)
+	}
+}
+
+func TestLoadLevels(t *testing.T) {
+	defer SetLevels(defaultLevels)
+
+	filename := filepath.Join(t.TempDir(), "levels.json")
+	data := `[{"name": "expert", "rows": 20, "columns": 20, "obstacleDensity": 0.1, "generator": "backbite"}]`
+	if err := os.WriteFile(filename, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadLevels(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := GenerateSeededBoard("expert", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(generated.Board) != 20 || len(generated.Board[0]) != 20 || len(generated.Solution) != 360 {
+		t.Errorf("expert board is %dx%d with %d open cells", len(generated.Board), len(generated.Board[0]), len(generated.Solution))
+	}
+	if _, err := GetLevel("beginner"); err == nil {
+		t.Errorf("beginner is still defined after loading the file")
+	}
+
+	invalid := []Level{
+		{Name: "", Rows: 5, Columns: 5, Generator: "backbite"},
+		{Name: "tiny", Rows: 1, Columns: 1, Generator: "backbite"},
+		{Name: "full", Rows: 5, Columns: 5, ObstacleDensity: 1, Generator: "backbite"},
+		{Name: "unknown", Rows: 5, Columns: 5, Generator: "nope"},
+	}
+	for _, level := range invalid {
+		if err := SetLevels([]Level{level}); err == nil {
+			t.Errorf("level %+v accepted", level)
+		}
+	}
+}
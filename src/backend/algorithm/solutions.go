@@ -0,0 +1,143 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Outcome of enumerating the solutions of a board
+type Enumeration struct {
+	Count    int    `json:"count"`
+	Complete bool   `json:"complete"` // Every solution was enumerated, Count is exact
+	TimedOut bool   `json:"timedOut"`
+	Reason   string `json:"reason,omitempty"` // Why the board was rejected without searching, if it was
+}
+
+// Shared state of one enumeration
+type enumerator struct {
+	limit   int
+	count   int
+	stopped bool
+	yield   func(path [][2]int) bool
+}
+
+// Count the solutions of a board, stopping after limit solutions (0 means no limit)
+func CountSolutions(ctx context.Context, board [][]int, limit int, opts Options) Enumeration {
+	return EnumerateSolutions(ctx, board, limit, opts, nil)
+}
+
+// Enumerate every distinct path from the starting dot that visits every usable dot.
+// yield receives each solution (its own copy) as soon as it is found, returning false stops the enumeration.
+// Stops after limit solutions (0 means no limit) or when the budget runs out.
+func EnumerateSolutions(ctx context.Context, board [][]int, limit int, opts Options, yield func(path [][2]int) bool) Enumeration {
+	fmt.Fprintln(logOutput, "[EnumerateSolutions] starting algorithm")
+
+	graph, startID, err := BoardToGraph(board)
+	if err != nil {
+		return Enumeration{Complete: true, Reason: err.Error()}
+	}
+
+	s := newSearch(ctx, opts)
+	s.usePruner(len(graph.Nodes))
+
+	e := &enumerator{limit: limit, yield: yield}
+	visited := make([]bool, len(graph.Nodes))
+	path := make([]int, 0, len(graph.Nodes))
+
+	EnumerateRecursive(s, graph, startID, visited, &path, e)
+
+	return Enumeration{
+		Count:    e.count,
+		Complete: !e.stopped && !s.exceeded.Load(),
+		TimedOut: s.exceeded.Load(),
+	}
+}
+
+// Extend the path with currentID and enumerate every solution starting with it.
+// Returns false once the enumeration must stop.
+func EnumerateRecursive(s *search, graph *Graph, currentID int, visited []bool, path *[]int, e *enumerator) bool {
+	if !s.expand(len(*path) + 1) {
+		return false
+	}
+
+	visited[currentID] = true
+	*path = append(*path, currentID)
+	cell := [2]int{graph.Nodes[currentID].X, graph.Nodes[currentID].Y}
+	s.tracePush(cell, len(*path))
+	defer func() {
+		// Backtrack
+		s.stats.Backtracks++
+		visited[currentID] = false
+		*path = (*path)[:len(*path)-1]
+		s.traceBacktrack(cell, len(*path))
+	}()
+
+	if len(*path) == len(graph.Nodes) {
+		e.count++
+		if e.yield != nil && !e.yield(idsToPath(graph, *path)) {
+			e.stopped = true
+			return false
+		}
+		if e.limit > 0 && e.count >= e.limit {
+			e.stopped = true
+			return false
+		}
+		return true
+	}
+
+	// Skip branches that can no longer be completed
+	if s.pruner != nil && s.pruner.graphDeadEnd(graph, visited, currentID, len(graph.Nodes)-len(*path)) {
+		s.stats.Pruned++
+		return true
+	}
+
+	for _, neighbor := range graph.Edges(currentID) {
+		if !visited[neighbor] {
+			if !EnumerateRecursive(s, graph, neighbor, visited, path, e) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Uniqueness of the solution of a board
+type Uniqueness string
+
+const (
+	NoSolution     Uniqueness = "none"
+	UniqueSolution Uniqueness = "unique"
+	ManySolutions  Uniqueness = "many"
+	UnknownAmount  Uniqueness = "unknown" // The budget ran out before the answer was known
+)
+
+// Outcome of checking if a board has exactly one solution
+type UniquenessCheck struct {
+	Uniqueness Uniqueness `json:"uniqueness"`
+	Paths      [][][2]int `json:"paths,omitempty"`  // The solution, or two differing solutions as a witness
+	Reason     string     `json:"reason,omitempty"` // Why the board was rejected without searching, if it was
+}
+
+// Check if a board has zero, one or many solutions, stopping as soon as a second solution is found
+func CheckUniqueness(ctx context.Context, board [][]int, opts Options) UniquenessCheck {
+	var paths [][][2]int
+	enumeration := EnumerateSolutions(ctx, board, 2, opts, func(path [][2]int) bool {
+		paths = append(paths, path)
+		return true
+	})
+
+	check := UniquenessCheck{Paths: paths, Reason: enumeration.Reason}
+	switch {
+	case len(paths) >= 2:
+		check.Uniqueness = ManySolutions
+	case enumeration.TimedOut:
+		check.Uniqueness = UnknownAmount
+	case len(paths) == 1:
+		check.Uniqueness = UniqueSolution
+	default:
+		check.Uniqueness = NoSolution
+	}
+
+	return check
+}
@@ -0,0 +1,94 @@
+package algorithm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCheckUniqueness(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    [][]int
+		want     Uniqueness
+		rejected bool // Rejected by the pre-checks, with a reason
+	}{
+		{"none", [][]int{{1, 1, 0, 1}, {0, 0, 0, 0}, {0, 0, 2, 0}}, NoSolution, false},
+		{"unique", [][]int{{2, 0, 0}, {1, 1, 0}}, UniqueSolution, false},
+		{"many", [][]int{{2, 0, 0}, {0, 0, 0}, {0, 0, 0}}, ManySolutions, false},
+		{"parity", [][]int{{0, 2, 0}, {0, 0, 0}, {0, 0, 0}}, NoSolution, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := CheckUniqueness(context.Background(), tt.board, Options{})
+			if check.Uniqueness != tt.want {
+				t.Fatalf("got %s, want %s", check.Uniqueness, tt.want)
+			}
+			wantReason := ""
+			if _, _, err := BoardToGraph(tt.board); tt.rejected && err != nil {
+				wantReason = err.Error()
+			}
+			if check.Reason != wantReason || tt.rejected && wantReason == "" {
+				t.Errorf("got reason %q, want %q", check.Reason, wantReason)
+			}
+
+			wantPaths := map[Uniqueness]int{NoSolution: 0, UniqueSolution: 1, ManySolutions: 2}[tt.want]
+			if len(check.Paths) != wantPaths {
+				t.Fatalf("got %d paths, want %d", len(check.Paths), wantPaths)
+			}
+			for _, path := range check.Paths {
+				if err := VerifyPath(tt.board, path); err != nil {
+					t.Errorf("invalid path %v: %v", path, err)
+				}
+			}
+			if wantPaths == 2 && reflect.DeepEqual(check.Paths[0], check.Paths[1]) {
+				t.Errorf("the two witness paths are the same: %v", check.Paths[0])
+			}
+		})
+	}
+}
+
+func TestCountSolutions(t *testing.T) {
+	open := [][]int{
+		{2, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	}
+
+	// Pruning skips dead branches only, it finds the same paths
+	for _, opts := range []Options{{}, {DisablePruning: true}} {
+		count := CountSolutions(context.Background(), open, 0, opts)
+		if count.Count != 52 || !count.Complete || count.TimedOut {
+			t.Errorf("pruning disabled %v: got %+v, want 52 complete", opts.DisablePruning, count)
+		}
+	}
+
+	limited := CountSolutions(context.Background(), open, 10, Options{})
+	if limited.Count != 10 || limited.Complete {
+		t.Errorf("limit 10: got %+v, want 10 incomplete", limited)
+	}
+}
+
+func TestEnumerateSolutionsStops(t *testing.T) {
+	open := [][]int{
+		{2, 0, 0},
+		{0, 0, 0},
+		{0, 0, 0},
+	}
+
+	var paths [][][2]int
+	enumeration := EnumerateSolutions(context.Background(), open, 0, Options{}, func(path [][2]int) bool {
+		paths = append(paths, path)
+		return len(paths) < 3
+	})
+	if len(paths) != 3 || enumeration.Count != 3 || enumeration.Complete {
+		t.Fatalf("got %d paths and %+v, want 3 incomplete", len(paths), enumeration)
+	}
+	for _, path := range paths {
+		if err := VerifyPath(open, path); err != nil {
+			t.Errorf("invalid path %v: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Solver is the common interface implemented by every dot-connect algorithm
+type Solver interface {
+	// Name is the key the solver is registered under (used in /solve/:algorithm)
+	Name() string
+	// Solve searches a path from the starting dot that visits every usable dot.
+	// The search stops with StatusTimedOut once ctx is done or opts are exceeded.
+	Solve(ctx context.Context, board [][]int, opts Options) Result
+}
+
+// Registered solvers by name
+var solvers = make(map[string]Solver)
+
+// Where the solvers log their progress
+var logOutput io.Writer = os.Stdout
+
+// Send the progress logs of the solvers to w instead of stdout, io.Discard silences them.
+// Must be called before any solver runs.
+func SetLogOutput(w io.Writer) {
+	logOutput = w
+}
+
+// Register a Solver so it can be looked up by name.
+// Registering two solvers with the same name is a programming error.
+func Register(s Solver) {
+	name := s.Name()
+	if _, exists := solvers[name]; exists {
+		panic(fmt.Sprintf("algorithm: solver %q registered twice", name))
+	}
+	solvers[name] = s
+}
+
+// Get the Solver registered under name
+func GetSolver(name string) (Solver, error) {
+	s, ok := solvers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown algorithm: %s", name)
+	}
+	return s, nil
+}
+
+// Names of all registered solvers, sorted
+func SolverNames() []string {
+	names := make([]string, 0, len(solvers))
+	for name := range solvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
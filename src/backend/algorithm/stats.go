@@ -0,0 +1,27 @@
+package algorithm
+
+// Statistics of a solver run, collected by the recursive search
+type Stats struct {
+	NodesExpanded int64 `json:"nodesExpanded"`
+	Backtracks    int64 `json:"backtracks"`
+	MaxDepth      int   `json:"maxDepth"` // Longest path tried, in dots
+	Pruned        int64 `json:"pruned"`   // Branches cut by the dead end pruning
+	TimeNs        int64 `json:"timeNs"`
+
+	// States looked up in the memo of dead states, how many were found and the share they make
+	TranspositionLookups int64   `json:"transpositionLookups"`
+	TranspositionHits    int64   `json:"transpositionHits"`
+	TranspositionHitRate float64 `json:"transpositionHitRate"`
+}
+
+// Add the statistics of another goroutine of the same run
+func (st *Stats) merge(other Stats) {
+	st.NodesExpanded += other.NodesExpanded
+	st.Backtracks += other.Backtracks
+	st.Pruned += other.Pruned
+	st.TranspositionLookups += other.TranspositionLookups
+	st.TranspositionHits += other.TranspositionHits
+	if other.MaxDepth > st.MaxDepth {
+		st.MaxDepth = other.MaxDepth
+	}
+}
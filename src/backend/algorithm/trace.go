@@ -0,0 +1,56 @@
+package algorithm
+
+import "sync"
+
+// Type of a search trace event
+type EventType string
+
+const (
+	EventPush      EventType = "push"      // A dot was added to the path
+	EventBacktrack EventType = "backtrack" // A dot was removed from the path
+	EventLayer     EventType = "layer"     // The DP extended every path by one dot, it sends no push nor backtrack while searching
+)
+
+// Event of the search trace, sent to Options.Trace while a solver runs
+type Event struct {
+	Type   EventType `json:"type"`
+	Cell   *[2]int   `json:"cell,omitempty"`   // Dot pushed or backtracked, nil for a layer
+	Depth  int       `json:"depth"`            // Length of the path after the event, or of the paths of a layer
+	Worker int       `json:"worker"`           // Goroutine of MainAlgo that made the move, 0 for the other solvers
+	States int       `json:"states,omitempty"` // Paths of a layer, by their set of dots and their last dot
+}
+
+// Report a dot added to the path, making it depth dots long
+func (s *search) tracePush(cell [2]int, depth int) {
+	if s.trace != nil {
+		s.trace(Event{Type: EventPush, Cell: &cell, Depth: depth, Worker: s.worker})
+	}
+}
+
+// Report a dot removed from the path, leaving depth dots
+func (s *search) traceBacktrack(cell [2]int, depth int) {
+	if s.trace != nil {
+		s.trace(Event{Type: EventBacktrack, Cell: &cell, Depth: depth, Worker: s.worker})
+	}
+}
+
+// Report a layer of states paths of depth dots
+func (s *search) traceLayer(depth, states int) {
+	if s.trace != nil {
+		s.trace(Event{Type: EventLayer, Depth: depth, Worker: s.worker, States: states})
+	}
+}
+
+// Wrap a trace hook so it can be called from several goroutines
+func serializeTrace(trace func(Event)) func(Event) {
+	if trace == nil {
+		return nil
+	}
+
+	var mu sync.Mutex
+	return func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		trace(e)
+	}
+}
@@ -0,0 +1,112 @@
+package algorithm
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTraceEventsFormPaths(t *testing.T) {
+	for _, boardName := range []string{"testBeginner", "testMedium"} {
+		board := loadTestBoard(t, boardName)
+
+		for _, name := range SolverNames() {
+			// The DP traces layers, and brute force would take the whole budget on testMedium
+			if name == "dp" || (name == "bf" && boardName == "testMedium") {
+				continue
+			}
+
+			// Replay the events of every worker on a path of its own
+			paths := make(map[int][][2]int)
+			var bad *Event
+			trace := func(e Event) {
+				path := paths[e.Worker]
+				switch {
+				case bad != nil:
+				case e.Type == EventPush && e.Depth == len(path)+1 && (len(path) == 0 || adjacent(path[len(path)-1], *e.Cell)):
+					paths[e.Worker] = append(path, *e.Cell)
+				case e.Type == EventBacktrack && e.Depth == len(path)-1 && path[len(path)-1] == *e.Cell:
+					paths[e.Worker] = path[:len(path)-1]
+				default:
+					bad = &e
+				}
+			}
+
+			solver, _ := GetSolver(name)
+			result := solver.Solve(context.Background(), board, Options{MaxNodes: testNodeBudget, Trace: trace})
+			if bad != nil {
+				t.Errorf("%s %s: event %+v does not follow the path of its worker", boardName, name, *bad)
+				continue
+			}
+			if name == "main" && boardName == "testMedium" && runtime.GOMAXPROCS(0) > 1 && len(paths) < 2 {
+				t.Errorf("%s %s: only %d worker traced", boardName, name, len(paths))
+			}
+			if !result.Found() {
+				continue
+			}
+
+			// The worker that found the solution ends on it
+			solved := false
+			for _, path := range paths {
+				solved = solved || reflect.DeepEqual(path, result.Path)
+			}
+			if !solved {
+				t.Errorf("%s %s: no worker ends on the solution", boardName, name)
+			}
+		}
+	}
+}
+
+func TestTraceTimeIsNotBudgeted(t *testing.T) {
+	// DFS proves testMedium has no solution in about 8000 nodes, the hook spends more than
+	// the whole time budget on their events
+	board := loadTestBoard(t, "testMedium")
+
+	events := 0
+	trace := func(e Event) {
+		events++
+		for start := time.Now(); time.Since(start) < 50*time.Microsecond; {
+		}
+	}
+	for _, name := range []string{"dfs", "main"} {
+		events = 0
+		solver, _ := GetSolver(name)
+		result := solver.Solve(context.Background(), board, Options{MaxTime: 300 * time.Millisecond, Trace: trace})
+		if result.Status != StatusNotFound {
+			t.Errorf("%s: got %s after %d events", name, result.Status, events)
+		}
+	}
+}
+
+func TestDPTracesLayers(t *testing.T) {
+	board := loadTestBoard(t, "testBeginner")
+
+	var events []Event
+	result := dpSolver{}.Solve(context.Background(), board, Options{Trace: func(e Event) { events = append(events, e) }})
+	if !result.Found() {
+		t.Fatalf("dp: %s", result.Status)
+	}
+
+	// A layer for every path length from 2 dots to one short of the solution, then the solution
+	n := len(result.Path)
+	if len(events) != n-2+n {
+		t.Fatalf("got %d events, want %d", len(events), n-2+n)
+	}
+	for i, e := range events[:n-2] {
+		if e.Type != EventLayer || e.Cell != nil || e.Depth != i+2 || e.States == 0 {
+			t.Errorf("event %d: %+v, want a layer of %d dots", i, e, i+2)
+		}
+	}
+	for i, e := range events[n-2:] {
+		if e.Type != EventPush || e.Depth != i+1 || *e.Cell != result.Path[i] {
+			t.Errorf("event %d: %+v, want the push of %v", n-2+i, e, result.Path[i])
+		}
+	}
+}
+
+// Check if two cells are orthogonal neighbours
+func adjacent(a, b [2]int) bool {
+	return abs(a[0]-b[0])+abs(a[1]-b[1]) == 1
+}
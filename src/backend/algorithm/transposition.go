@@ -0,0 +1,82 @@
+package algorithm
+
+// Slots of a new memo, it grows from there up to the size of the options
+const initialTranspositionSize = 1 << 12
+
+// Memo of search states known to lead nowhere.
+// A state is the set of visited dots together with the head of the path: whether the path
+// can still be completed only depends on those, not on the order the dots were visited in.
+// States are keyed by a Zobrist hash, the XOR of one random key per visited dot and one for
+// the head, so pushing or popping a dot updates the hash in constant time.
+// The table starts small, so short searches do not pay for a large allocation, and doubles
+// while more than half full, up to a fixed amount of keys; a new state overwrites the one in its slot.
+// Two states sharing a 64 bit key would make the search skip a live state, which is accepted as
+// far less likely than the budget running out.
+type transpositionTable struct {
+	keys    []uint64
+	mask    uint64
+	maxSize int // Slots the table may grow to
+	used    int // Slots holding a key
+}
+
+// Create a table of at most size entries, rounded down to a power of two
+func newTranspositionTable(size int) *transpositionTable {
+	maxSize := 1
+	for maxSize*2 <= size {
+		maxSize *= 2
+	}
+	n := min(maxSize, initialTranspositionSize)
+	return &transpositionTable{keys: make([]uint64, n), mask: uint64(n - 1), maxSize: maxSize}
+}
+
+// Keys are stored with their lowest bit set, 0 marks an empty slot.
+// The slot comes from the other bits, so a stored key still gives its slot once the table grows.
+func (t *transpositionTable) slot(key uint64) uint64 {
+	return (key >> 1) & t.mask
+}
+
+func (t *transpositionTable) contains(key uint64) bool {
+	return t.keys[t.slot(key)] == key|1
+}
+
+func (t *transpositionTable) add(key uint64) {
+	i := t.slot(key)
+	if t.keys[i] == 0 {
+		t.used++
+	}
+	t.keys[i] = key | 1
+
+	if 2*t.used > len(t.keys) && len(t.keys) < t.maxSize {
+		t.grow()
+	}
+}
+
+// Double the slots, the keys of a slot i move to i or i+len(keys) so none is lost
+func (t *transpositionTable) grow() {
+	keys := t.keys
+	t.keys = make([]uint64, 2*len(keys))
+	t.mask = uint64(len(t.keys) - 1)
+	for _, key := range keys {
+		if key != 0 {
+			t.keys[t.slot(key)] = key
+		}
+	}
+}
+
+// Zobrist key of a dot when visited, from its index
+func visitedKey(i int) uint64 {
+	return splitMix64(uint64(2 * i))
+}
+
+// Zobrist key of a dot when it is the head of the path, from its index
+func headKey(i int) uint64 {
+	return splitMix64(uint64(2*i + 1))
+}
+
+// Well mixed 64 bit value of x, a fixed function so keys need no table nor seed
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
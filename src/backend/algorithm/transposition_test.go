@@ -0,0 +1,154 @@
+package algorithm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// Memo size and node budget of the tests
+const (
+	testTranspositionSize = 1 << 16
+	testNodeBudget        = 200000
+)
+
+func TestTranspositionsKeepResults(t *testing.T) {
+	boards := randomTestBoards(7, 300)
+	for _, level := range Levels() {
+		for seed := int64(0); seed < 3; seed++ {
+			generated, _ := generateBoard(level.Rows, level.Columns, level.freeCells(), rand.New(rand.NewSource(seed)))
+			boards = append(boards, generated.Board)
+		}
+	}
+
+	for _, board := range boards {
+		for _, solver := range []Solver{dfsSolver{}, greedySolver{}} {
+			for _, prune := range []bool{true, false} {
+				want := solver.Solve(context.Background(), board, Options{DisablePruning: !prune, MaxNodes: testNodeBudget})
+				got := solver.Solve(context.Background(), board, Options{DisablePruning: !prune, MaxNodes: testNodeBudget, TranspositionSize: testTranspositionSize})
+				if want.Status == StatusTimedOut {
+					continue
+				}
+
+				if got.Status != want.Status {
+					t.Fatalf("%s on %v: %s with the memo, %s without", solver.Name(), board, got.Status, want.Status)
+				}
+				if got.Found() {
+					if err := VerifyPath(board, got.Path); err != nil {
+						t.Fatalf("%s on %v: invalid path with the memo: %v", solver.Name(), board, err)
+					}
+				}
+				if got.Stats.NodesExpanded > want.Stats.NodesExpanded {
+					t.Errorf("%s on %v: memo expanded %d nodes, %d without", solver.Name(), board, got.Stats.NodesExpanded, want.Stats.NodesExpanded)
+				}
+			}
+		}
+	}
+}
+
+func TestTranspositionHitRate(t *testing.T) {
+	// Without pruning the open 4x4 corner is explored over and over
+	board := [][]int{
+		{2, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 1},
+		{0, 0, 0, 1, 0},
+	}
+
+	result := DFS(context.Background(), mustBoardToGraph(t, board), 0, Options{DisablePruning: true, TranspositionSize: testTranspositionSize})
+	if result.Found() {
+		t.Fatalf("found a path on an unsolvable board")
+	}
+	st := result.Stats
+	if st.TranspositionHits == 0 || st.TranspositionHitRate != float64(st.TranspositionHits)/float64(st.TranspositionLookups) {
+		t.Errorf("unexpected memo stats: %+v", st)
+	}
+}
+
+func TestTranspositionTableGrows(t *testing.T) {
+	table := newTranspositionTable(1 << 16)
+	if len(table.keys) != initialTranspositionSize {
+		t.Fatalf("new table of %d slots, want %d", len(table.keys), initialTranspositionSize)
+	}
+
+	// Keys held before the table doubles are still there after
+	var held []uint64
+	for i := 0; i < initialTranspositionSize/2; i++ {
+		table.add(splitMix64(uint64(i)))
+	}
+	for i := 0; i < initialTranspositionSize/2; i++ {
+		if table.contains(splitMix64(uint64(i))) {
+			held = append(held, splitMix64(uint64(i)))
+		}
+	}
+	table.grow()
+	for _, key := range held {
+		if !table.contains(key) {
+			t.Fatalf("key %x lost when the table grew", key)
+		}
+	}
+
+	// The table never grows past its size
+	for i := 0; i < 1<<18; i++ {
+		table.add(splitMix64(uint64(i) + 1<<32))
+	}
+	if len(table.keys) != 1<<16 {
+		t.Errorf("table grew to %d slots, want %d", len(table.keys), 1<<16)
+	}
+}
+
+func TestTranspositionsUsedOnSmallBoards(t *testing.T) {
+	board := [][]int{
+		{2, 0, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	}
+
+	result := DFS(context.Background(), mustBoardToGraph(t, board), 0, Options{TranspositionSize: testTranspositionSize})
+	if !result.Found() || result.Stats.TranspositionLookups == 0 {
+		t.Errorf("memo not used on a small board: %s %+v", result.Status, result.Stats)
+	}
+}
+
+func mustBoardToGraph(tb testing.TB, board [][]int) *Graph {
+	tb.Helper()
+
+	graph, _, err := buildGraph(board)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return graph
+}
+
+func BenchmarkTranspositions(b *testing.B) {
+	boards := map[string][][]int{
+		"testMedium": loadTestBoard(b, "testMedium"),
+	}
+	hard, _ := GetLevel("hard")
+	for seed := int64(1); seed <= 3; seed++ {
+		generated, _ := generateBoard(hard.Rows, hard.Columns, hard.freeCells(), rand.New(rand.NewSource(seed)))
+		boards[fmt.Sprintf("hard%d", seed)] = generated.Board
+	}
+
+	for name, board := range boards {
+		for _, solver := range []Solver{dfsSolver{}, greedySolver{}} {
+			for _, bench := range []struct {
+				name string
+				opts Options
+			}{
+				{"memo", Options{MaxNodes: 2000000, TranspositionSize: 1 << 20}},
+				{"plain", Options{MaxNodes: 2000000}},
+			} {
+				b.Run(name+"/"+solver.Name()+"/"+bench.name, func(b *testing.B) {
+					var result Result
+					for i := 0; i < b.N; i++ {
+						result = solver.Solve(context.Background(), board, bench.opts)
+					}
+					b.ReportMetric(float64(result.Stats.NodesExpanded), "nodes/op")
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package algorithm
+
+import "fmt"
+
+// Kind of rule a path breaks
+type Violation string
+
+const (
+	ViolationInvalidBoard Violation = "invalid_board"
+	ViolationEmpty        Violation = "empty"
+	ViolationWrongStart   Violation = "wrong_start"
+	ViolationOutOfBoard   Violation = "out_of_board"
+	ViolationBlocked      Violation = "blocked"
+	ViolationNotAdjacent  Violation = "not_adjacent"
+	ViolationRepeated     Violation = "repeated"
+	ViolationIncomplete   Violation = "incomplete"
+)
+
+// PathError describes the first rule a path breaks
+type PathError struct {
+	Violation Violation `json:"violation"`
+	Index     int       `json:"index"`            // Position of the offending dot in the path
+	Cell      [2]int    `json:"cell"`             // The offending dot, or the first dot left out for ViolationIncomplete
+	Reason    string    `json:"reason,omitempty"` // Why the board itself is invalid, for ViolationInvalidBoard
+}
+
+func (e *PathError) Error() string {
+	r, c := e.Cell[0], e.Cell[1]
+
+	switch e.Violation {
+	case ViolationInvalidBoard:
+		return "invalid board: " + e.Reason
+	case ViolationEmpty:
+		return "path is empty"
+	case ViolationWrongStart:
+		return fmt.Sprintf("does not begin at the starting dot, begins at (%d, %d)", r, c)
+	case ViolationOutOfBoard:
+		return fmt.Sprintf("dot %d at (%d, %d) is outside of the board", e.Index, r, c)
+	case ViolationBlocked:
+		return fmt.Sprintf("dot %d at (%d, %d) is blocked", e.Index, r, c)
+	case ViolationNotAdjacent:
+		return fmt.Sprintf("dot %d at (%d, %d) is not next to the previous dot", e.Index, r, c)
+	case ViolationRepeated:
+		return fmt.Sprintf("dot %d at (%d, %d) is visited twice", e.Index, r, c)
+	case ViolationIncomplete:
+		return fmt.Sprintf("path ends after %d dots, dot (%d, %d) is not connected", e.Index, r, c)
+	default:
+		return fmt.Sprintf("dot %d at (%d, %d) is invalid", e.Index, r, c)
+	}
+}
+
+// VerifyPath checks that path is a solution of board: it starts at the single starting dot,
+// moves one orthogonal step at a time, never repeats a dot, avoids blocked dots
+// and covers every usable dot. Returns a *PathError for the first violation.
+func VerifyPath(board [][]int, path [][2]int) error {
+	if len(path) == 0 {
+		return &PathError{Violation: ViolationEmpty}
+	}
+
+	visited, err := verifySteps(board, path)
+	if err != nil {
+		return err
+	}
+
+	for r := range board {
+		for c := range board[r] {
+			if board[r][c] != 1 && !visited[[2]int{r, c}] {
+				return &PathError{Violation: ViolationIncomplete, Index: len(path), Cell: [2]int{r, c}}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check every step of a path or the beginning of one, returns the visited dots
+func verifySteps(board [][]int, path [][2]int) (map[[2]int]bool, error) {
+	start, err := checkBoard(board)
+	if err != nil {
+		return nil, &PathError{Violation: ViolationInvalidBoard, Reason: err.Error()}
+	}
+
+	visited := make(map[[2]int]bool, len(path))
+
+	for i, cell := range path {
+		r, c := cell[0], cell[1]
+		switch {
+		case r < 0 || r >= len(board) || c < 0 || c >= len(board[0]):
+			return nil, &PathError{Violation: ViolationOutOfBoard, Index: i, Cell: cell}
+		case board[r][c] == 1:
+			return nil, &PathError{Violation: ViolationBlocked, Index: i, Cell: cell}
+		case i == 0 && cell != start:
+			return nil, &PathError{Violation: ViolationWrongStart, Index: i, Cell: cell}
+		case i > 0 && abs(r-path[i-1][0])+abs(c-path[i-1][1]) != 1:
+			return nil, &PathError{Violation: ViolationNotAdjacent, Index: i, Cell: cell}
+		case visited[cell]:
+			return nil, &PathError{Violation: ViolationRepeated, Index: i, Cell: cell}
+		}
+		visited[cell] = true
+	}
+
+	return visited, nil
+}
@@ -0,0 +1,99 @@
+package algorithm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyPath(t *testing.T) {
+	board := [][]int{
+		{2, 0, 0},
+		{1, 1, 0},
+		{0, 0, 0},
+	}
+
+	tests := []struct {
+		name string
+		path [][2]int
+		want Violation // empty for a valid path
+	}{
+		{"solution", [][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 2}, {2, 1}, {2, 0}}, ""},
+		{"empty", nil, ViolationEmpty},
+		{"wrong start", [][2]int{{0, 1}, {0, 0}}, ViolationWrongStart},
+		{"outside", [][2]int{{0, 0}, {-1, 0}}, ViolationOutOfBoard},
+		{"blocked", [][2]int{{0, 0}, {1, 0}}, ViolationBlocked},
+		{"diagonal", [][2]int{{0, 0}, {0, 1}, {1, 2}}, ViolationNotAdjacent},
+		{"jump", [][2]int{{0, 0}, {0, 2}}, ViolationNotAdjacent},
+		{"repeated", [][2]int{{0, 0}, {0, 1}, {0, 0}}, ViolationRepeated},
+		{"incomplete", [][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 2}}, ViolationIncomplete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyPath(board, tt.path)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("got %v, want valid", err)
+				}
+				return
+			}
+
+			var pathErr *PathError
+			if !errors.As(err, &pathErr) || pathErr.Violation != tt.want {
+				t.Fatalf("got %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyPathRejectsInvalidBoards(t *testing.T) {
+	tests := []struct {
+		name  string
+		board [][]int
+		path  [][2]int
+	}{
+		{"two starts", [][]int{{2, 0, 2}}, [][2]int{{0, 0}, {0, 1}, {0, 2}}},
+		{"two starts reversed", [][]int{{2, 0, 2}}, [][2]int{{0, 2}, {0, 1}, {0, 0}}},
+		{"no start", [][]int{{0, 0, 0}}, [][2]int{{0, 0}, {0, 1}, {0, 2}}},
+		{"ragged", [][]int{{2, 0}, {0}}, [][2]int{{0, 0}, {0, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pathErr *PathError
+			if err := VerifyPath(tt.board, tt.path); !errors.As(err, &pathErr) || pathErr.Violation != ViolationInvalidBoard {
+				t.Fatalf("got %v, want %s", err, ViolationInvalidBoard)
+			}
+
+			// No solver picks one of the starting dots either
+			for _, name := range SolverNames() {
+				solver, _ := GetSolver(name)
+				if result := solver.Solve(context.Background(), tt.board, Options{}); result.Found() {
+					t.Errorf("%s solved the board with %v", name, result.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestSolverPathsAreValid(t *testing.T) {
+	boards := randomTestBoards(2, 200)
+	for _, name := range []string{"testBeginner", "testEasy", "testMedium", "testHard"} {
+		boards = append(boards, loadTestBoard(t, name))
+	}
+
+	for i, board := range boards {
+		for _, name := range SolverNames() {
+			solver, _ := GetSolver(name)
+			result := solver.Solve(context.Background(), board, Options{MaxTime: time.Second})
+
+			if result.Found() {
+				if err := VerifyPath(board, result.Path); err != nil {
+					t.Errorf("board %d: %s returned an invalid path: %v", i, name, err)
+				}
+			}
+		}
+	}
+}
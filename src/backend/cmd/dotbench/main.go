@@ -0,0 +1,268 @@
+// Command dotbench runs every registered solver over a corpus of boards and reports
+// how often each one answers within the time budget, how long it takes and how many
+// nodes it expands.
+//
+// The corpus is either every board JSON file of a directory:
+//
+//	go run ./cmd/dotbench -dir ../../test
+//
+// or boards generated from seeds for each level:
+//
+//	go run ./cmd/dotbench -n 20 -levels easy,medium -format csv
+package main
+
+import (
+	"context"
+	"dot-connect-api/algorithm"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Board of the corpus
+type corpusBoard struct {
+	Name  string
+	Board [][]int
+}
+
+// Outcome of one solver over the corpus
+type report struct {
+	Solver      string  `json:"solver"`
+	Boards      int     `json:"boards"`
+	Found       int     `json:"found"`
+	NotFound    int     `json:"notFound"`
+	TimedOut    int     `json:"timedOut"`
+	Unsupported int     `json:"unsupported"`
+	Invalid     int     `json:"invalid"`     // Found paths that do not solve the board
+	SuccessRate float64 `json:"successRate"` // Share of boards answered with a valid result within the budget
+	MedianMs    float64 `json:"medianMs"`
+	P95Ms       float64 `json:"p95Ms"`
+	MedianNodes int64   `json:"medianNodes"`
+	P95Nodes    int64   `json:"p95Nodes"`
+}
+
+func main() {
+	dir := flag.String("dir", "", "directory of board JSON files, boards are generated when empty")
+	n := flag.Int("n", 10, "boards generated per level")
+	seed := flag.Int64("seed", 1, "seed of the first generated board")
+	levels := flag.String("levels", "", "comma separated levels to generate, all when empty")
+	levelsFile := flag.String("levels-file", "", "level definitions to load instead of the built-in ones")
+	solvers := flag.String("solvers", "", "comma separated solvers to run, all registered when empty")
+	timeout := flag.Duration("timeout", 10*time.Second, "time budget of every solve")
+	memo := flag.Int("memo", 1<<20, "size of the memo of dead states, 0 disables it")
+	format := flag.String("format", "table", "output format: table, csv or json")
+	verbose := flag.Bool("v", false, "keep the log lines of the solvers")
+	flag.Parse()
+
+	if *format != "table" && *format != "csv" && *format != "json" {
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	if *levelsFile != "" {
+		if err := algorithm.LoadLevels(*levelsFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// The solvers log to stdout, which holds the report
+	if !*verbose {
+		algorithm.SetLogOutput(io.Discard)
+	}
+
+	var corpus []corpusBoard
+	var err error
+	if *dir != "" {
+		corpus, err = loadCorpus(*dir)
+	} else {
+		corpus, err = generateCorpus(splitList(*levels), *n, *seed)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(corpus) == 0 {
+		log.Fatal("no boards to run")
+	}
+
+	names := splitList(*solvers)
+	if len(names) == 0 {
+		names = algorithm.SolverNames()
+	}
+
+	opts := algorithm.Options{MaxTime: *timeout, TranspositionSize: *memo}
+	var reports []report
+	for _, name := range names {
+		solver, err := algorithm.GetSolver(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "running %s over %d boards\n", name, len(corpus))
+		reports = append(reports, run(solver, corpus, opts))
+	}
+
+	if err := write(os.Stdout, reports, *format); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Load every .json file of dir holding a {"board": [...]} object
+func loadCorpus(dir string) ([]corpusBoard, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var corpus []corpusBoard
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var content struct {
+			Board [][]int `json:"board"`
+		}
+		if err := json.Unmarshal(data, &content); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if len(content.Board) == 0 || len(content.Board[0]) == 0 {
+			return nil, fmt.Errorf("%s: empty board", file)
+		}
+
+		corpus = append(corpus, corpusBoard{Name: filepath.Base(file), Board: content.Board})
+	}
+
+	return corpus, nil
+}
+
+// Generate n boards for each level, from consecutive seeds
+func generateCorpus(levels []string, n int, seed int64) ([]corpusBoard, error) {
+	if len(levels) == 0 {
+		for _, level := range algorithm.Levels() {
+			levels = append(levels, level.Name)
+		}
+	}
+
+	var corpus []corpusBoard
+	for _, level := range levels {
+		for i := int64(0); i < int64(n); i++ {
+			generated, err := algorithm.GenerateSeededBoard(level, seed+i)
+			if err != nil {
+				return nil, err
+			}
+			corpus = append(corpus, corpusBoard{Name: fmt.Sprintf("%s/%d", level, seed+i), Board: generated.Board})
+		}
+	}
+
+	return corpus, nil
+}
+
+// Solve every board of the corpus with solver
+func run(solver algorithm.Solver, corpus []corpusBoard, opts algorithm.Options) report {
+	r := report{Solver: solver.Name(), Boards: len(corpus)}
+	times := make([]float64, 0, len(corpus))
+	nodes := make([]int64, 0, len(corpus))
+
+	for _, board := range corpus {
+		start := time.Now()
+		result := solver.Solve(context.Background(), board.Board, opts)
+		times = append(times, float64(time.Since(start).Microseconds())/1000)
+		nodes = append(nodes, result.Stats.NodesExpanded)
+
+		switch result.Status {
+		case algorithm.StatusFound:
+			if err := algorithm.VerifyPath(board.Board, result.Path); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: invalid path on %s: %v\n", solver.Name(), board.Name, err)
+				r.Invalid++
+			} else {
+				r.Found++
+			}
+		case algorithm.StatusNotFound:
+			r.NotFound++
+		case algorithm.StatusTimedOut:
+			r.TimedOut++
+		case algorithm.StatusUnsupported:
+			r.Unsupported++
+		}
+	}
+
+	r.SuccessRate = float64(r.Found+r.NotFound) / float64(r.Boards)
+	r.MedianMs, r.P95Ms = percentile(times, 50), percentile(times, 95)
+	r.MedianNodes, r.P95Nodes = percentile(nodes, 50), percentile(nodes, 95)
+
+	return r
+}
+
+// Nearest rank percentile p of values, sorts values
+func percentile[T int64 | float64](values []T, p int) T {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	rank := (p*len(values) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return values[rank-1]
+}
+
+// Print the reports to out in format
+func write(out io.Writer, reports []report, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+
+	case "csv":
+		w := csv.NewWriter(out)
+		w.Write([]string{"solver", "boards", "found", "notFound", "timedOut", "unsupported", "invalid",
+			"successRate", "medianMs", "p95Ms", "medianNodes", "p95Nodes"})
+		for _, r := range reports {
+			w.Write([]string{
+				r.Solver, strconv.Itoa(r.Boards), strconv.Itoa(r.Found), strconv.Itoa(r.NotFound),
+				strconv.Itoa(r.TimedOut), strconv.Itoa(r.Unsupported), strconv.Itoa(r.Invalid),
+				strconv.FormatFloat(r.SuccessRate, 'f', 4, 64),
+				strconv.FormatFloat(r.MedianMs, 'f', 3, 64), strconv.FormatFloat(r.P95Ms, 'f', 3, 64),
+				strconv.FormatInt(r.MedianNodes, 10), strconv.FormatInt(r.P95Nodes, 10),
+			})
+		}
+		w.Flush()
+		return w.Error()
+
+	case "table":
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "solver\tboards\tfound\tnot found\ttimed out\tunsupported\tinvalid\tsuccess\tmedian\tp95\tmedian nodes\tp95 nodes\t")
+		for _, r := range reports {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%.1f%%\t%.3fms\t%.3fms\t%d\t%d\t\n",
+				r.Solver, r.Boards, r.Found, r.NotFound, r.TimedOut, r.Unsupported, r.Invalid,
+				100*r.SuccessRate, r.MedianMs, r.P95Ms, r.MedianNodes, r.P95Nodes)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// Split a comma separated list, ignoring empty items
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
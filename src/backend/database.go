@@ -2,7 +2,10 @@ package main
 
 import (
 	"database/sql"
+	"dot-connect-api/algorithm"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -24,19 +27,7 @@ func initDB() {
 		CREATE TABLE IF NOT EXISTS users (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			username TEXT UNIQUE NOT NULL,
-			password TEXT NOT NULL,
-			bot_custom_beginner INTEGER DEFAULT NULL,
-			bot_custom_easy INTEGER DEFAULT NULL,
-			bot_custom_medium INTEGER DEFAULT NULL,
-			bot_custom_hard INTEGER DEFAULT NULL,
-			manual_random_beginner INTEGER DEFAULT NULL,
-			manual_random_easy INTEGER DEFAULT NULL,
-			manual_random_medium INTEGER DEFAULT NULL,
-			manual_random_hard INTEGER DEFAULT NULL,
-			manual_custom_beginner INTEGER DEFAULT NULL,
-			manual_custom_easy INTEGER DEFAULT NULL,
-			manual_custom_medium INTEGER DEFAULT NULL,
-			manual_custom_hard INTEGER DEFAULT NULL
+			password TEXT NOT NULL
 		);
 		`
 	_, err = db.Exec(userTableCreationSQL)
@@ -61,6 +52,86 @@ func initDB() {
 	if err != nil {
 		PrintlnRed("[Main] DATABASE ERROR: " + err.Error())
 	}
+
+	// Create highscores table if it does not exist, one row per user, mode, board type and level
+	var hasHighscores bool
+	row := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'highscores')`)
+	if err = row.Scan(&hasHighscores); err != nil {
+		PrintlnRed("[Main] DATABASE ERROR: " + err.Error())
+	}
+
+	highscoreTableCreationSQL := `
+		CREATE TABLE IF NOT EXISTS highscores (
+		username TEXT NOT NULL,
+		mode TEXT NOT NULL,
+		boardType TEXT NOT NULL,
+		level TEXT NOT NULL,
+		score INTEGER NOT NULL,
+		PRIMARY KEY(username, mode, boardType, level),
+		FOREIGN KEY(username) REFERENCES users(username)
+	);
+	`
+	_, err = db.Exec(highscoreTableCreationSQL)
+	if err != nil {
+		PrintlnRed("[Main] DATABASE ERROR: " + err.Error())
+	}
+
+	if !hasHighscores {
+		migrateHighscores()
+	}
+
+	// Create solutions table if it does not exist, one row per canonical board and algorithm.
+	// The path is stored as JSON on the canonical board, and so are the stats of the solve.
+	solutionTableCreationSQL := `
+		CREATE TABLE IF NOT EXISTS solutions (
+		hash TEXT NOT NULL,
+		algorithm TEXT NOT NULL,
+		status TEXT NOT NULL,
+		path TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		solveTimeNs INTEGER NOT NULL,
+		stats TEXT NOT NULL,
+		date DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY(hash, algorithm)
+	);
+	`
+	_, err = db.Exec(solutionTableCreationSQL)
+	if err != nil {
+		PrintlnRed("[Main] DATABASE ERROR: " + err.Error())
+	}
+}
+
+// Copy the highscores of older databases, stored in users as one <mode>_<boardType>_<level> column each,
+// into the highscores table
+func migrateHighscores() {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('users')`)
+	if err != nil {
+		PrintlnRed("[Main] DATABASE ERROR: " + err.Error())
+		return
+	}
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			PrintlnRed("[Main] DATABASE ERROR: " + err.Error())
+			rows.Close()
+			return
+		}
+		if len(strings.Split(column, "_")) == 3 {
+			columns = append(columns, column)
+		}
+	}
+	rows.Close()
+
+	for _, column := range columns {
+		parts := strings.Split(column, "_")
+		_, err := db.Exec(`INSERT OR IGNORE INTO highscores (username, mode, boardType, level, score)
+			SELECT username, ?, ?, ?, `+column+` FROM users WHERE `+column+` IS NOT NULL`, parts[0], parts[1], parts[2])
+		if err != nil {
+			PrintlnRed("[Main] DATABASE ERROR: " + err.Error())
+		}
+	}
 }
 
 // Register function to add a new user
@@ -89,9 +160,10 @@ func register(username string, password string) bool {
 
 // Update the highscore
 func updateHighscore(username string, mode string, level string, boardType string, score int) bool {
-	columnName := mode + "_" + boardType + "_" + level
-
-	_, err := db.Exec(`UPDATE users SET `+columnName+` = ? WHERE username = ? AND (`+columnName+` IS NULL OR `+columnName+` > ?)`, score, username, score)
+	_, err := db.Exec(`
+		INSERT INTO highscores (username, mode, boardType, level, score) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(username, mode, boardType, level) DO UPDATE SET score = excluded.score
+		WHERE excluded.score < highscores.score`, username, mode, boardType, level, score)
 	if err != nil {
 		PrintlnRed("[Main] Error Updating Highscore: " + err.Error())
 		return false
@@ -126,17 +198,15 @@ func addGameHistory(username string, mode string, level string, boardType string
 
 // Retrieve the leaderboard (top 5 fastest users)
 func getLeaderboard(mode string, level string, boardType string) ([]map[string]interface{}, error) {
-	columnName := mode + "_" + boardType + "_" + level
-
 	query := `
-	SELECT username, ` + columnName + ` as bestTime
-	FROM users
-	WHERE ` + columnName + ` IS NOT NULL
-	ORDER BY ` + columnName + ` ASC
+	SELECT username, score as bestTime
+	FROM highscores
+	WHERE mode = ? AND boardType = ? AND level = ?
+	ORDER BY score ASC
 	LIMIT 5;
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, mode, boardType, level)
 	if err != nil {
 		PrintlnRed("[Database] Error executing query: " + err.Error())
 		return nil, err
@@ -186,10 +256,11 @@ func login(username string, password string) bool {
 
 // Check if the score is better than the highscore
 func isNewHighscore(username string, mode string, level string, score int, boardType string) (bool, error) {
-	columnName := mode + "_" + boardType + "_" + level
-
+	// No row when the user does not exist, NULL when they have no highscore yet
 	var currentHighscore *int
-	row := db.QueryRow(`SELECT `+columnName+` FROM users WHERE username = ?`, username)
+	row := db.QueryRow(`
+		SELECT (SELECT score FROM highscores WHERE username = users.username AND mode = ? AND boardType = ? AND level = ?)
+		FROM users WHERE username = ?`, mode, boardType, level, username)
 	err := row.Scan(&currentHighscore)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -263,6 +334,56 @@ func getHistory(username string) ([]map[string]interface{}, error) {
 	return history, nil
 }
 
+// Solution store backed by the solutions table
+type databaseStore struct{}
+
+// Load the solution of a canonical board hash by an algorithm
+func (databaseStore) Load(key algorithm.SolutionKey) (algorithm.StoredSolution, bool) {
+	var solution algorithm.StoredSolution
+	var status, path, stats string
+	row := db.QueryRow(`SELECT status, path, reason, algorithm, solveTimeNs, stats FROM solutions WHERE hash = ? AND algorithm = ?`,
+		key.Hash, key.Algorithm)
+	err := row.Scan(&status, &path, &solution.Reason, &solution.Algorithm, &solution.TimeNs, &stats)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			PrintlnRed("[Database] Error Loading Solution: " + err.Error())
+		}
+		return solution, false
+	}
+
+	solution.Status = algorithm.Status(status)
+	if err := json.Unmarshal([]byte(path), &solution.Path); err != nil {
+		PrintlnRed("[Database] Error Loading Solution: " + err.Error())
+		return solution, false
+	}
+	if err := json.Unmarshal([]byte(stats), &solution.Stats); err != nil {
+		PrintlnRed("[Database] Error Loading Solution: " + err.Error())
+		return solution, false
+	}
+
+	return solution, true
+}
+
+// Save the solution of a canonical board hash by an algorithm
+func (databaseStore) Save(key algorithm.SolutionKey, solution algorithm.StoredSolution) {
+	path, err := json.Marshal(solution.Path)
+	if err != nil {
+		PrintlnRed("[Database] Error Saving Solution: " + err.Error())
+		return
+	}
+	stats, err := json.Marshal(solution.Stats)
+	if err != nil {
+		PrintlnRed("[Database] Error Saving Solution: " + err.Error())
+		return
+	}
+
+	_, err = db.Exec(`INSERT OR REPLACE INTO solutions (hash, algorithm, status, path, reason, solveTimeNs, stats) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		key.Hash, key.Algorithm, string(solution.Status), string(path), solution.Reason, solution.TimeNs, string(stats))
+	if err != nil {
+		PrintlnRed("[Database] Error Saving Solution: " + err.Error())
+	}
+}
+
 func init() {
 	initDB()
 }
@@ -2,6 +2,9 @@ package main
 
 import (
 	"dot-connect-api/algorithm"
+	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -14,8 +17,36 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Longest time a solver may run for a single request
+const maxSolveTime = 30 * time.Second
+
+// Dead states remembered by DFS and Greedy during a request, 8 bytes each
+const transpositionSize = 1 << 20
+
+// Boards whose solution is kept in memory
+const solutionCacheSize = 10000
+
+// Solutions of the boards solved so far, by canonical board, kept in memory and in the database
+var solutionCache = algorithm.StoreChain{algorithm.NewMemoryStore(solutionCacheSize), databaseStore{}}
+
+// Events per second sent by the Trace Endpoint
+const (
+	defaultTraceRate = 20
+	maxTraceRate     = 1000
+)
+
+// Longest time spent looking for a board of the requested difficulty
+const maxGenerateTime = 5 * time.Second
+
+// Most solutions streamed by a single /solutions/list request
+const maxListedSolutions = 1000
+
+// Level definitions, the built-in levels are used when the file does not exist
+const levelsFile = "./levels.json"
+
 func main() {
 	initDB()
+	loadLevels()
 
 	// Starting API
 	PrintlnYellow("[Main] Dot-Game API started...")
@@ -73,11 +104,13 @@ func main() {
 	// Add Game History Endpoint
 	r.POST("/addGameHistory", func(c *gin.Context) {
 		var gameHistory struct {
-			Username  string `json:"username"`
-			Mode      string `json:"mode"`
-			Level     string `json:"level"`
-			BoardType string `json:"boardType"`
-			Score     int    `json:"score"`
+			Username  string   `json:"username"`
+			Mode      string   `json:"mode"`
+			Level     string   `json:"level"`
+			BoardType string   `json:"boardType"`
+			Score     int      `json:"score"`
+			Board     [][]int  `json:"board"`
+			Path      [][2]int `json:"path"`
 		}
 
 		if err := c.BindJSON(&gameHistory); err != nil {
@@ -102,6 +135,21 @@ func main() {
 			boardType = "custom"
 		}
 
+		if !validScoreKey(mode, level, boardType) {
+			PrintlnRed("[Main] Request Failed, Invalid Mode, Level, or Board Type")
+			c.JSON(http.StatusBadRequest, gin.H{"response": "BAD QUERY"})
+			return
+		}
+
+		// Reject claimed solutions that do not solve the board
+		if gameHistory.Board != nil || gameHistory.Path != nil {
+			if err := algorithm.VerifyPath(gameHistory.Board, gameHistory.Path); err != nil {
+				PrintlnRed("[Main] Request Failed, Invalid Path: " + err.Error())
+				c.JSON(http.StatusBadRequest, gin.H{"response": "INVALID PATH", "message": err.Error()})
+				return
+			}
+		}
+
 		// Add game history
 		success := addGameHistory(username, mode, level, boardType, score)
 		if success {
@@ -177,16 +225,60 @@ func main() {
 			return
 		}
 
-		// Generate the board
-		board, err := algorithm.GenerateRandomBoard(level)
+		seed := algorithm.NewSeed()
+		if seedStr := c.Query("seed"); seedStr != "" {
+			var err error
+			seed, err = strconv.ParseInt(seedStr, 10, 64)
+			if err != nil || seed > algorithm.MaxSeed || seed < -algorithm.MaxSeed {
+				PrintlnRed("[Main] Invalid Seed Format")
+				c.JSON(http.StatusBadRequest, gin.H{"response": "INVALID SEED"})
+				return
+			}
+		}
+
+		// Optional target difficulty, as a tier name or a score range
+		band, hasBand, err := difficultyBand(c)
+		if err != nil {
+			PrintlnRed("[Main] Invalid Difficulty: " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"response": "INVALID DIFFICULTY"})
+			return
+		}
+
+		// Generate the board, solvable by construction and reproducible with the seed
+		var generated *algorithm.GeneratedBoard
+		inBand := true
+		if hasBand {
+			generated, err = algorithm.GenerateBoardWithDifficulty(c.Request.Context(), level, band, seed, maxGenerateTime)
+			if errors.Is(err, algorithm.ErrDifficultyNotReached) {
+				// Fall back to the closest board found
+				inBand, err = false, nil
+			}
+		} else {
+			generated, err = algorithm.GenerateSeededBoard(level, seed)
+			if err == nil {
+				generated.Rate(c.Request.Context())
+			}
+		}
 		if err != nil {
 			PrintlnRed("[Main] Error Generating Random Board: " + err.Error())
 			c.JSON(http.StatusInternalServerError, gin.H{"response": "ERROR", "message": err.Error()})
 			return
 		}
 
-		// Return the board as JSON
-		c.JSON(http.StatusOK, gin.H{"board": board})
+		// Return the board as JSON, with the known solution if requested
+		response := gin.H{"board": generated.Board, "seed": generated.Seed, "difficulty": generated.Difficulty}
+		if hasBand {
+			response["inBand"] = inBand
+		}
+		if c.Query("withSolution") == "true" {
+			response["solution"] = generated.Solution
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	// Levels Endpoint
+	r.GET("/levels", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"levels": algorithm.Levels()})
 	})
 
 	// Check if score is better than highscore
@@ -245,172 +337,360 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"history": history})
 	})
 
-	// Solve DFS Endpoint
-	r.POST("/solvedfs", func(c *gin.Context) {
-		var requestData struct {
-			Board [][]int `json:"board"`
-		}
+	// Solve Endpoint, dispatches to any registered algorithm
+	r.POST("/solve/:algorithm", func(c *gin.Context) {
+		solveBoard(c, c.Param("algorithm"))
+	})
 
-		if err := c.BindJSON(&requestData); err != nil {
-			PrintlnRed("[Main] Invalid JSON Format")
-			c.JSON(http.StatusBadRequest, gin.H{"response": "BAD REQUEST"})
+	// Trace Endpoint, streams the search of any registered algorithm
+	r.POST("/solve/:algorithm/trace", func(c *gin.Context) {
+		traceBoard(c, c.Param("algorithm"))
+	})
+
+	// Per algorithm Solve Endpoints (/solvedfs, /solvebf, /solvegreed, ...)
+	for _, name := range algorithm.SolverNames() {
+		r.POST("/solve"+name, func(c *gin.Context) {
+			solveBoard(c, name)
+		})
+	}
+
+	// Count Solutions Endpoint
+	r.POST("/solutions/count", func(c *gin.Context) {
+		requestData, ok := bindBoardRequest(c)
+		if !ok {
 			return
 		}
 
-		board := requestData.Board
-
-		// Start timer
 		startTime := time.Now()
 
-		graph, startID, err := algorithm.BoardToGraph(board)
-		if err != nil {
-			duration := time.Since(startTime)
+		enumeration := algorithm.CountSolutions(c.Request.Context(), requestData.Board, requestData.Limit, requestData.options())
 
-			response := gin.H{
-				"found": false,
-				"time":  duration.Milliseconds(),
-			}
+		c.JSON(http.StatusOK, gin.H{
+			"solutions": enumeration,
+			"time":      time.Since(startTime).Milliseconds(),
+		})
+	})
 
-			c.JSON(http.StatusOK, response)
-		} else {
-			path, found := algorithm.DFS(graph, startID)
+	// List Solutions Endpoint, streams one JSON object per line
+	r.POST("/solutions/list", func(c *gin.Context) {
+		requestData, ok := bindBoardRequest(c)
+		if !ok {
+			return
+		}
 
-			duration := time.Since(startTime)
+		limit := requestData.Limit
+		if limit <= 0 || limit > maxListedSolutions {
+			limit = maxListedSolutions
+		}
 
-			response := gin.H{
-				"found": found,
-				"time":  duration.Milliseconds(),
-			}
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		encoder := json.NewEncoder(c.Writer)
 
-			if found {
-				response["path"] = path
-			} else {
-				response["message"] = "No solution found"
+		enumeration := algorithm.EnumerateSolutions(c.Request.Context(), requestData.Board, limit, requestData.options(), func(path [][2]int) bool {
+			if err := encoder.Encode(gin.H{"path": path}); err != nil {
+				return false
 			}
+			c.Writer.Flush()
+			return true
+		})
 
-			c.JSON(http.StatusOK, response)
-		}
+		encoder.Encode(gin.H{"solutions": enumeration})
 	})
 
-	// Solve Brute Force Endpoint
-	r.POST("/solvebf", func(c *gin.Context) {
-		var requestData struct {
-			Board [][]int `json:"board"`
-		}
-
-		if err := c.BindJSON(&requestData); err != nil {
-			PrintlnRed("[Main] Invalid JSON Format")
-			c.JSON(http.StatusBadRequest, gin.H{"response": "BAD REQUEST"})
+	// Unique Solution Check Endpoint
+	r.POST("/solutions/unique", func(c *gin.Context) {
+		requestData, ok := bindBoardRequest(c)
+		if !ok {
 			return
 		}
 
-		board := requestData.Board
-
-		// Start timer
 		startTime := time.Now()
 
-		graph, startID, _ := algorithm.BoardToGraph(board)
-		path, found := algorithm.DFS(graph, startID)
+		check := algorithm.CheckUniqueness(c.Request.Context(), requestData.Board, requestData.options())
 
-		duration := time.Since(startTime)
+		c.JSON(http.StatusOK, gin.H{
+			"uniqueness": check,
+			"time":       time.Since(startTime).Milliseconds(),
+		})
+	})
 
-		response := gin.H{
-			"found": found,
-			"time":  duration.Milliseconds(),
+	// Hint Endpoint, next move for the player's partial path
+	r.POST("/hint", func(c *gin.Context) {
+		requestData, ok := bindBoardRequest(c)
+		if !ok {
+			return
 		}
 
-		if found {
-			response["path"] = path
-		} else {
-			response["message"] = "No solution found"
-		}
+		startTime := time.Now()
 
-		c.JSON(http.StatusOK, response)
+		hint, err := algorithm.GetHint(c.Request.Context(), requestData.Board, requestData.Path, requestData.options())
+		if err != nil {
+			PrintlnRed("[Main] Request Failed, " + err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"response": "INVALID PATH", "message": err.Error()})
+			return
+		}
 
+		c.JSON(http.StatusOK, gin.H{
+			"hint": hint,
+			"time": time.Since(startTime).Milliseconds(),
+		})
 	})
 
-	// Solve Greedy Algorithm Endpoint
-	r.POST("/solvegreed", func(c *gin.Context) {
-		var requestData struct {
-			Board [][]int `json:"board"`
+	// Verify Path Endpoint
+	r.POST("/verify", func(c *gin.Context) {
+		requestData, ok := bindBoardRequest(c)
+		if !ok {
+			return
 		}
 
-		if err := c.BindJSON(&requestData); err != nil {
-			PrintlnRed("[Main] Invalid JSON Format")
-			c.JSON(http.StatusBadRequest, gin.H{"response": "BAD REQUEST"})
+		err := algorithm.VerifyPath(requestData.Board, requestData.Path)
+
+		var pathErr *algorithm.PathError
+		if errors.As(err, &pathErr) {
+			c.JSON(http.StatusOK, gin.H{
+				"valid":   false,
+				"error":   pathErr,
+				"message": pathErr.Error(),
+			})
 			return
 		}
 
-		board := requestData.Board
+		c.JSON(http.StatusOK, gin.H{"valid": true})
+	})
+
+	// Difficulty Rating Endpoint
+	r.POST("/difficulty", func(c *gin.Context) {
+		requestData, ok := bindBoardRequest(c)
+		if !ok {
+			return
+		}
 
-		// Start timer
 		startTime := time.Now()
 
-		path, found := algorithm.Greedy(board)
+		difficulty := algorithm.RateDifficulty(c.Request.Context(), requestData.Board, nil)
 
-		duration := time.Since(startTime)
+		c.JSON(http.StatusOK, gin.H{
+			"difficulty": difficulty,
+			"time":       time.Since(startTime).Milliseconds(),
+		})
+	})
 
-		response := gin.H{
-			"found": found,
-			"time":  duration.Milliseconds(),
+	// Start server in a goroutine
+	go func() {
+		PrintlnGreen("[Main] Listening on port " + port)
+		if err := r.Run("0.0.0.0:" + port); err != nil {
+			log.Fatalf("Server failed to start: %v", err)
 		}
+	}()
 
-		if found {
-			response["path"] = path
-		} else {
-			response["message"] = "No solution found"
+	<-quit
+
+	PrintlnRed("[Main] Shutting down server...")
+}
+
+// Solve the board in the request body using the algorithm registered as name
+func solveBoard(c *gin.Context, name string) {
+	solver, err := algorithm.GetSolver(name)
+	if err != nil {
+		PrintlnRed("[Main] Request Failed, " + err.Error())
+		c.JSON(http.StatusNotFound, gin.H{"response": "UNKNOWN ALGORITHM"})
+		return
+	}
+
+	requestData, ok := bindBoardRequest(c)
+	if !ok {
+		return
+	}
+
+	// The time is the one of the solver run, kept with the solution when it was cached
+	result, run, cached := algorithm.SolveCached(c.Request.Context(), solutionCache, solver, requestData.Board, requestData.options())
+
+	response := solveResponse(result, time.Duration(run.TimeNs))
+	response["cached"] = cached
+	response["algorithm"] = run.Algorithm
+	c.JSON(http.StatusOK, response)
+}
+
+// Trace the search for the board in the request body using the algorithm registered as name.
+// Every push and backtrack is sent as a Server-Sent Event, at most rate events per second,
+// and a final "result" event holds the same response as the Solve Endpoint.
+// The events of the parallel solver carry the worker whose path they change, the DP sends "layer" events.
+// The time the solver waits for the stream is left out of its time budget.
+func traceBoard(c *gin.Context, name string) {
+	solver, err := algorithm.GetSolver(name)
+	if err != nil {
+		PrintlnRed("[Main] Request Failed, " + err.Error())
+		c.JSON(http.StatusNotFound, gin.H{"response": "UNKNOWN ALGORITHM"})
+		return
+	}
+
+	rate := defaultTraceRate
+	if rateStr := c.Query("rate"); rateStr != "" {
+		rate, err = strconv.Atoi(rateStr)
+		if err != nil || rate <= 0 || rate > maxTraceRate {
+			PrintlnRed("[Main] Invalid Trace Rate")
+			c.JSON(http.StatusBadRequest, gin.H{"response": "INVALID RATE"})
+			return
 		}
+	}
 
-		c.JSON(http.StatusOK, response)
+	requestData, ok := bindBoardRequest(c)
+	if !ok {
+		return
+	}
 
-	})
+	ctx := c.Request.Context()
+	events := make(chan algorithm.Event)
+	results := make(chan gin.H, 1)
 
-	// Solve Main Algorithm Endpoint
-	// NOT FINISHED -- look at algorithm/mainAlgo.go
-	// r.POST("/solvemain", func(c *gin.Context) {
-	// 	var requestData struct {
-	// 		Board [][]int `json:"board"`
-	// 	}
+	// The solver waits for every event to be sent, so it runs at the pace of the stream
+	opts := requestData.options()
+	opts.Trace = func(e algorithm.Event) {
+		select {
+		case events <- e:
+		case <-ctx.Done():
+		}
+	}
 
-	// 	if err := c.BindJSON(&requestData); err != nil {
-	// 		PrintlnRed("[Main] Invalid JSON Format")
-	// 		c.JSON(http.StatusBadRequest, gin.H{"response": "BAD REQUEST"})
-	// 		return
-	// 	}
+	go func() {
+		startTime := time.Now()
+		result := solver.Solve(ctx, requestData.Board, opts)
+		results <- solveResponse(result, time.Since(startTime))
+	}()
 
-	// 	board := requestData.Board
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case response := <-results:
+			c.SSEvent("result", response)
+			return false
+		case e := <-events:
+			c.SSEvent(string(e.Type), e)
+			<-ticker.C
+			return true
+		}
+	})
+}
 
-	// 	// Start timer
-	// 	startTime := time.Now()
+// Response of the Solve Endpoint for a finished solver run
+func solveResponse(result algorithm.Result, duration time.Duration) gin.H {
+	response := gin.H{
+		"found":  result.Found(),
+		"status": result.Status,
+		"time":   duration.Milliseconds(),
+		"stats":  result.Stats,
+	}
+
+	switch result.Status {
+	case algorithm.StatusFound:
+		response["path"] = result.Path
+	case algorithm.StatusTimedOut:
+		response["message"] = "Search timed out"
+	case algorithm.StatusUnsupported:
+		response["message"] = "Board not supported by this algorithm"
+		response["reason"] = result.Reason
+	default:
+		response["message"] = "No solution found"
+		if result.Reason != "" {
+			response["reason"] = result.Reason
+		}
+	}
+
+	return response
+}
 
-	// 	path, found := algorithm.MainAlgo(board)
+// Body of the requests working on a single board
+type boardRequest struct {
+	Board     [][]int  `json:"board"`
+	MaxTimeMs int64    `json:"maxTimeMs"`
+	MaxNodes  int64    `json:"maxNodes"`
+	Limit     int      `json:"limit"`
+	Path      [][2]int `json:"path"`
+}
 
-	// 	duration := time.Since(startTime)
+// Read a boardRequest from the body, responds with BAD REQUEST and returns false if it is invalid
+func bindBoardRequest(c *gin.Context) (boardRequest, bool) {
+	var requestData boardRequest
 
-	// 	response := gin.H{
-	// 		"found": found,
-	// 		"time":  duration.Milliseconds(),
-	// 	}
+	if err := c.BindJSON(&requestData); err != nil {
+		PrintlnRed("[Main] Invalid JSON Format")
+		c.JSON(http.StatusBadRequest, gin.H{"response": "BAD REQUEST"})
+		return requestData, false
+	}
 
-	// 	if found {
-	// 		response["path"] = path
-	// 	} else {
-	// 		response["message"] = "No solution found"
-	// 	}
+	if len(requestData.Board) == 0 || len(requestData.Board[0]) == 0 {
+		PrintlnRed("[Main] Request Failed, Empty Board")
+		c.JSON(http.StatusBadRequest, gin.H{"response": "BAD QUERY"})
+		return requestData, false
+	}
 
-	// 	c.JSON(http.StatusOK, response)
+	return requestData, true
+}
 
-	// })
+// Budget of the search, never longer than maxSolveTime
+func (requestData boardRequest) options() algorithm.Options {
+	opts := algorithm.Options{
+		MaxTime:           maxSolveTime,
+		MaxNodes:          requestData.MaxNodes,
+		TranspositionSize: transpositionSize,
+	}
+	if requestData.MaxTimeMs > 0 && time.Duration(requestData.MaxTimeMs)*time.Millisecond < maxSolveTime {
+		opts.MaxTime = time.Duration(requestData.MaxTimeMs) * time.Millisecond
+	}
+
+	return opts
+}
 
-	// Start server in a goroutine
-	go func() {
-		PrintlnGreen("[Main] Listening on port " + port)
-		if err := r.Run("0.0.0.0:" + port); err != nil {
-			log.Fatalf("Server failed to start: %v", err)
-		}
-	}()
+// Read the target difficulty of /generateRandom, either ?difficulty=<tier> or ?minDifficulty=&maxDifficulty=
+func difficultyBand(c *gin.Context) (algorithm.DifficultyBand, bool, error) {
+	if tier := c.Query("difficulty"); tier != "" {
+		band, err := algorithm.TierBand(tier)
+		return band, true, err
+	}
+
+	minStr, maxStr := c.Query("minDifficulty"), c.Query("maxDifficulty")
+	if minStr == "" && maxStr == "" {
+		return algorithm.DifficultyBand{}, false, nil
+	}
+
+	band := algorithm.DifficultyBand{Min: 0, Max: 101}
+	var err error
+	if minStr != "" {
+		if band.Min, err = strconv.ParseFloat(minStr, 64); err != nil {
+			return band, true, err
+		}
+	}
+	if maxStr != "" {
+		if band.Max, err = strconv.ParseFloat(maxStr, 64); err != nil {
+			return band, true, err
+		}
+	}
+	if band.Min >= band.Max {
+		return band, true, errors.New("empty difficulty range")
+	}
+
+	return band, true, nil
+}
 
-	<-quit
+// Load the level definitions from levelsFile
+func loadLevels() {
+	err := algorithm.LoadLevels(levelsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		PrintlnYellow("[Main] No " + levelsFile + ", using the built-in levels")
+	} else if err != nil {
+		PrintlnRed("[Main] Error Loading Levels, using the built-in levels: " + err.Error())
+	}
+}
 
-	PrintlnRed("[Main] Shutting down server...")
+// Check that scores can be recorded for the mode, level and board type
+func validScoreKey(mode string, level string, boardType string) bool {
+	if _, err := algorithm.GetLevel(level); err != nil {
+		return false
+	}
+	return (mode == "bot" || mode == "manual") && (boardType == "random" || boardType == "custom")
 }